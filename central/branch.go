@@ -0,0 +1,116 @@
+package central
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+// branchName returns the branch name of the ref ( false if the ref is not a branch, e.g. refs/pull/1/merge ).
+func branchName(ref string) (string, bool) {
+	if strings.HasPrefix(ref, "refs/heads/") {
+		return strings.TrimPrefix(ref, "refs/heads/"), true
+	}
+	if ref == "" || strings.HasPrefix(ref, "refs/") {
+		return "", false
+	}
+	return ref, true
+}
+
+// latestReportsByBranch returns the latest report of each branch. reports must be sorted by timestamp.
+func latestReportsByBranch(reports []*report.Report) map[string]*report.Report {
+	branches := map[string]*report.Report{}
+	for _, r := range reports {
+		b, ok := branchName(r.Ref)
+		if !ok {
+			continue
+		}
+		branches[b] = r
+	}
+	return branches
+}
+
+// defaultBranches gets the default branches of the repositories with the worker pool of Concurrency workers.
+// The repositories whose default branch could not be got are not in the result.
+func (c *Central) defaultBranches(ctx context.Context, repos []string) map[string]string {
+	branches := map[string]string{}
+	if c.config.DefaultBranch == nil {
+		return branches
+	}
+	mu := sync.Mutex{}
+	q := make(chan string)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < c.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range q {
+				b, err := c.config.DefaultBranch(ctx, repo)
+				if err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "Could not get the default branch of %s: %v\n", repo, err)
+					continue
+				}
+				mu.Lock()
+				branches[repo] = b
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, repo := range repos {
+		q <- repo
+	}
+	close(q)
+	wg.Wait()
+	return branches
+}
+
+// selectReport returns the latest report of the default branch of the repository.
+// If the default branch is unknown or has no reports, it returns the latest report of any branch ( or the latest report ).
+// reports must be sorted by timestamp.
+func (c *Central) selectReport(repo, defaultBranch string, reports []*report.Report) *report.Report {
+	if r, ok := c.branches[repo][defaultBranch]; ok && defaultBranch != "" {
+		return r
+	}
+	for i := len(reports) - 1; i >= 0; i-- {
+		if _, ok := branchName(reports[i].Ref); ok {
+			return reports[i]
+		}
+	}
+	return reports[len(reports)-1]
+}
+
+// isTrackedBranch returns true if the branch matches one of TrackedBranches ( glob patterns ).
+func (c *Central) isTrackedBranch(branch string) bool {
+	for _, p := range c.config.TrackedBranches {
+		if ok, _ := path.Match(p, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// branchesDir is the directory of the badges of the branches in the directory of the repository.
+// The branches are nested under it so that a branch name never collides with the other files of the repository ( e.g. history ).
+const branchesDir = "branches"
+
+// generateBranchBadges generates the badges of the default branch and the tracked branches at [owner]/[repo]/branches/[branch]/.
+func (c *Central) generateBranchBadges(badges map[string][]byte) error {
+	for _, r := range c.reports {
+		selected, _ := branchName(r.Ref)
+		for b, br := range c.branches[r.Repository] {
+			if b != selected && !c.isTrackedBranch(b) {
+				continue
+			}
+			if err := c.renderReportBadges(badges, br, filepath.Join(r.Repository, branchesDir, filepath.FromSlash(b))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
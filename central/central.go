@@ -4,13 +4,10 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
-	"encoding/json"
 	"fmt"
 	"io"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -29,6 +26,18 @@ var indexTmpl []byte
 type Central struct {
 	config  *CentralConfig
 	reports []*report.Report
+	// history is all reports collected for each repository, sorted by timestamp
+	history map[string][]*report.Report
+	// badges is the generated badges keyed by path
+	badges map[string][]byte
+	// branches is the latest report of each branch of each repository
+	branches map[string]map[string]*report.Report
+	// groups is the reports grouped by Groups
+	groups []*GroupReports
+	// changed is the paths of the badges put into the badges datastores because their content is changed
+	changed map[string]struct{}
+	// previous is the repositories that had badges in the badges datastores before generating
+	previous map[string]struct{}
 }
 
 type CentralConfig struct {
@@ -40,17 +49,39 @@ type CentralConfig struct {
 	CoverageColor          func(cover float64) string
 	CodeToTestRatioColor   func(ratio float64) string
 	TestExecutionTimeColor func(d time.Duration) string
+	BadgeStyle             string
+	BadgeFormats           []string
+	BadgeTrend             bool
+	BadgeTrendLimit        int
+	HTML                   string
+	Template               string
+	StaleAfter             time.Duration
+	HideStale              bool
+	DefaultBranch          func(ctx context.Context, repository string) (string, error)
+	BranchBadges           bool
+	TrackedBranches        []string
+	Concurrency            int
+	Groups                 []*Group
+	Topics                 func(ctx context.Context, repository string) ([]string, error)
+	History                bool
+	SummaryThresholds      []float64
+	Feed                   bool
+	FeedThresholds         map[string]float64
 }
 
 func New(c *CentralConfig) *Central {
 	return &Central{
-		config: c,
+		config:  c,
+		changed: map[string]struct{}{},
 	}
 }
 
+// Generate generates the badges, the index, the feed and the HTML site, and returns the local paths of the changed files.
 func (c *Central) Generate(ctx context.Context) ([]string, error) {
+	c.previous = c.badgedRepositories()
+
 	// collect reports
-	if err := c.collectReports(); err != nil {
+	if err := c.collectReports(ctx); err != nil {
 		return nil, err
 	}
 
@@ -60,130 +91,191 @@ func (c *Central) Generate(ctx context.Context) ([]string, error) {
 		return nil, err
 	}
 
+	// generate badges of owners
+	sp, err := c.generateSummaryBadges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, sp...)
+
+	// generate badges of groups
+	c.groups = c.groupReports(ctx)
+	gp, err := c.generateGroupBadges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, gp...)
+
+	// generate history
+	if c.config.History {
+		files, err := c.generateHistory()
+		if err != nil {
+			return nil, err
+		}
+		hp, err := c.putBadges(ctx, files)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, hp...)
+		for path, content := range files {
+			c.badges[path] = content
+		}
+	}
+
 	// render index
 	p := c.config.Index
 	fi, err := os.Stat(c.config.Index)
 	if err == nil && fi.IsDir() {
 		p = filepath.Join(c.config.Index, "README.md")
 	}
-	i, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	d, err := c.indexData()
 	if err != nil {
 		return nil, err
 	}
-	if err := c.renderIndex(i); err != nil {
+	buf := new(bytes.Buffer)
+	if err := c.renderIndex(buf, d); err != nil {
 		return nil, err
 	}
-	paths = append(paths, p)
-
-	return paths, nil
-}
+	changed, err := writeFileIfChanged(p, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		paths = append(paths, p)
+	}
 
-func (c *Central) collectReports() error {
-	rsMap := map[string]*report.Report{}
+	// render index.json
+	jp := filepath.Join(c.indexDir(), "index.json")
+	buf = new(bytes.Buffer)
+	if err := c.renderIndexJSON(buf, d); err != nil {
+		return nil, err
+	}
+	changed, err = writeFileIfChanged(jp, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		paths = append(paths, jp)
+	}
 
-	// collect reports
-	for _, d := range c.config.Reports {
-		fsys, err := d.FS()
+	// render feed.xml
+	if c.config.Feed {
+		fp := filepath.Join(c.indexDir(), "feed.xml")
+		buf = new(bytes.Buffer)
+		if err := c.renderFeed(buf, d); err != nil {
+			return nil, err
+		}
+		changed, err = writeFileIfChanged(fp, buf.Bytes())
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
-				return nil
-			}
-			r := &report.Report{}
-			f, err := fsys.Open(path)
-			if err != nil {
-				return nil
-			}
-			defer f.Close()
-			b, err := io.ReadAll(f)
-			if err != nil {
-				return nil
-			}
-			if err := json.Unmarshal(b, r); err != nil {
-				return nil
-			}
-			current, ok := rsMap[r.Repository]
-			if !ok {
-				_, _ = fmt.Fprintf(os.Stderr, "Collect report of %s\n", r.Repository)
-				rsMap[r.Repository] = r
-				return nil
-			}
-			if current.Timestamp.UnixNano() < r.Timestamp.UnixNano() {
-				rsMap[r.Repository] = r
-			}
-			return nil
-		}); err != nil {
-			return err
+		if changed {
+			paths = append(paths, fp)
 		}
 	}
 
-	for _, r := range rsMap {
-		c.reports = append(c.reports, r)
+	// generate HTML site
+	if c.config.HTML != "" {
+		hp, err := c.generateHTML()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, hp...)
 	}
-	sort.Slice(c.reports, func(i, j int) bool { return c.reports[i].Repository < c.reports[j].Repository })
-	return nil
+
+	// delete badges and pages of the repositories no longer reported
+	if len(c.reports) > 0 {
+		rp, err := c.removeRepositories(ctx, c.removedRepositories())
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, rp...)
+	}
+
+	return paths, nil
 }
 
 func (c *Central) generateBadges() ([]string, error) {
 	ctx := context.Background()
 	badges := map[string][]byte{}
 	for _, r := range c.reports {
-		cp := r.CoveragePercent()
-		bp := filepath.Join(r.Repository, "coverage.svg")
-		out := new(bytes.Buffer)
-		b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
-		b.MessageColor = c.config.CoverageColor(cp)
-		if err := b.AddIcon(internal.Icon); err != nil {
+		if err := c.renderReportBadges(badges, r, r.Repository); err != nil {
 			return nil, err
 		}
-		if err := b.Render(out); err != nil {
+		if c.config.BadgeTrend {
+			if err := c.generateTrendBadges(badges, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if c.config.BranchBadges {
+		if err := c.generateBranchBadges(badges); err != nil {
 			return nil, err
 		}
-		badges[bp] = out.Bytes()
+	}
+	c.badges = badges
+	return c.putBadges(ctx, badges)
+}
 
-		// Code to Test Ratio
-		if r.CodeToTestRatio != nil {
-			tr := r.CodeToTestRatioRatio()
-			bp := filepath.Join(r.Repository, "ratio.svg")
-			out := new(bytes.Buffer)
-			b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
-			b.MessageColor = c.config.CodeToTestRatioColor(tr)
-			if err := b.AddIcon(internal.Icon); err != nil {
-				return nil, err
-			}
-			if err := b.Render(out); err != nil {
-				return nil, err
-			}
-			badges[bp] = out.Bytes()
+// renderReportBadges renders the coverage, ratio and time badges of the report in dir.
+func (c *Central) renderReportBadges(badges map[string][]byte, r *report.Report, dir string) error {
+	cp := r.CoveragePercent()
+	b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
+	b.MessageColor = c.badgeColor(r, c.config.CoverageColor(cp))
+	b.Style = c.config.BadgeStyle
+	if err := b.AddIcon(internal.Icon); err != nil {
+		return err
+	}
+	if err := c.renderBadge(badges, b, filepath.Join(dir, "coverage")); err != nil {
+		return err
+	}
+
+	// Code to Test Ratio
+	if r.CodeToTestRatio != nil {
+		tr := r.CodeToTestRatioRatio()
+		b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
+		b.MessageColor = c.badgeColor(r, c.config.CodeToTestRatioColor(tr))
+		b.Style = c.config.BadgeStyle
+		if err := b.AddIcon(internal.Icon); err != nil {
+			return err
 		}
+		if err := c.renderBadge(badges, b, filepath.Join(dir, "ratio")); err != nil {
+			return err
+		}
+	}
 
-		// Test Execution Time
-		if r.TestExecutionTime != nil {
-			d := time.Duration(r.TestExecutionTimeNano())
-			bp := filepath.Join(r.Repository, "time.svg")
-			out := new(bytes.Buffer)
-			b := badge.New("test execution time", d.String())
-			b.MessageColor = c.config.TestExecutionTimeColor(d)
-			if err := b.AddIcon(internal.Icon); err != nil {
-				return nil, err
-			}
-			if err := b.Render(out); err != nil {
-				return nil, err
-			}
-			badges[bp] = out.Bytes()
+	// Test Execution Time
+	if r.TestExecutionTime != nil {
+		d := time.Duration(r.TestExecutionTimeNano())
+		b := badge.New("test execution time", d.String())
+		b.MessageColor = c.badgeColor(r, c.config.TestExecutionTimeColor(d))
+		b.Style = c.config.BadgeStyle
+		if err := b.AddIcon(internal.Icon); err != nil {
+			return err
+		}
+		if err := c.renderBadge(badges, b, filepath.Join(dir, "time")); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// putBadges puts the files whose content is changed into the badges datastores and returns the paths of the files put in local datastores.
+func (c *Central) putBadges(ctx context.Context, files map[string][]byte) ([]string, error) {
 	generatedPaths := []string{}
 	for _, d := range c.config.Badges {
-		for path, content := range badges {
+		fsys, err := d.FS()
+		if err != nil {
+			fsys = nil
+		}
+		for path, content := range files {
+			if isUnchanged(fsys, path, content) {
+				continue
+			}
 			if err := d.Put(ctx, path, content); err != nil {
 				return nil, err
 			}
+			c.changed[path] = struct{}{}
 			switch v := d.(type) {
 			case *local.Local:
 				generatedPaths = append(generatedPaths, filepath.Join(v.Root(), path))
@@ -193,8 +285,110 @@ func (c *Central) generateBadges() ([]string, error) {
 	return generatedPaths, nil
 }
 
-func (c *Central) renderIndex(wr io.Writer) error {
-	tmpl := template.Must(template.New("index").Funcs(funcs()).Parse(string(indexTmpl)))
+// generateTrendBadges generates the badges with the sparkline of the last BadgeTrendLimit values of each metric.
+// The color is based on the latest value.
+func (c *Central) generateTrendBadges(badges map[string][]byte, r *report.Report) error {
+	type trendBadge struct {
+		name     string
+		label    string
+		message  string
+		color    string
+		measured func(r *report.Report) bool
+		value    func(r *report.Report) float64
+	}
+	tbs := []trendBadge{}
+	if r.IsMeasuredCoverage() {
+		cp := r.CoveragePercent()
+		tbs = append(tbs, trendBadge{
+			"coverage-trend", "coverage", fmt.Sprintf("%.1f%%", cp), c.config.CoverageColor(cp),
+			func(r *report.Report) bool { return r.IsMeasuredCoverage() },
+			func(r *report.Report) float64 { return r.CoveragePercent() },
+		})
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		tr := r.CodeToTestRatioRatio()
+		tbs = append(tbs, trendBadge{
+			"ratio-trend", "code to test ratio", fmt.Sprintf("1:%.1f", tr), c.config.CodeToTestRatioColor(tr),
+			func(r *report.Report) bool { return r.IsMeasuredCodeToTestRatio() },
+			func(r *report.Report) float64 { return r.CodeToTestRatioRatio() },
+		})
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		d := time.Duration(r.TestExecutionTimeNano())
+		tbs = append(tbs, trendBadge{
+			"time-trend", "test execution time", d.String(), c.config.TestExecutionTimeColor(d),
+			func(r *report.Report) bool { return r.IsMeasuredTestExecutionTime() },
+			func(r *report.Report) float64 { return r.TestExecutionTimeNano() },
+		})
+	}
+	for _, tb := range tbs {
+		values := []float64{}
+		for _, rr := range c.history[r.Repository] {
+			if tb.measured(rr) {
+				values = append(values, tb.value(rr))
+			}
+		}
+		if c.config.BadgeTrendLimit > 0 && len(values) > c.config.BadgeTrendLimit {
+			values = values[len(values)-c.config.BadgeTrendLimit:]
+		}
+		b := badge.New(tb.label, tb.message)
+		b.MessageColor = c.badgeColor(r, tb.color)
+		b.Style = c.config.BadgeStyle
+		b.Trend = values
+		if err := b.AddIcon(internal.Icon); err != nil {
+			return err
+		}
+		if err := c.renderBadge(badges, b, filepath.Join(r.Repository, tb.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderBadge renders the badge in each format of BadgeFormats to the path without extension.
+func (c *Central) renderBadge(badges map[string][]byte, b *badge.Badge, path string) error {
+	formats := c.config.BadgeFormats
+	if len(formats) == 0 {
+		formats = []string{badge.FormatSVG}
+	}
+	for _, f := range formats {
+		switch f {
+		case badge.FormatSVG:
+			out := new(bytes.Buffer)
+			if err := b.Render(out); err != nil {
+				return err
+			}
+			badges[fmt.Sprintf("%s.svg", path)] = out.Bytes()
+		case badge.FormatPNG:
+			out := new(bytes.Buffer)
+			if err := b.RenderPNG(out, 1); err != nil {
+				return err
+			}
+			badges[fmt.Sprintf("%s.png", path)] = out.Bytes()
+			out2x := new(bytes.Buffer)
+			if err := b.RenderPNG(out2x, 2); err != nil {
+				return err
+			}
+			badges[fmt.Sprintf("%s@2x.png", path)] = out2x.Bytes()
+		case badge.FormatJSON:
+			if len(b.Trend) > 0 {
+				// the endpoint JSON can not represent the sparkline
+				continue
+			}
+			out := new(bytes.Buffer)
+			if err := b.RenderJSON(out); err != nil {
+				return err
+			}
+			badges[fmt.Sprintf("%s.json", path)] = out.Bytes()
+		default:
+			return fmt.Errorf("invalid badge format: %s", f)
+		}
+	}
+	return nil
+}
+
+// indexData returns the data passed to the index template.
+func (c *Central) indexData() (*IndexData, error) {
 	host := os.Getenv("GITHUB_SERVER_URL")
 	if host == "" {
 		host = gh.DefaultGithubServerURL
@@ -203,24 +397,21 @@ func (c *Central) renderIndex(wr io.Writer) error {
 	ctx := context.Background()
 	g, err := gh.New()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	repo, err := gh.Parse(c.config.Repository)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	rawRootURL, err := g.GetRawRootURL(ctx, repo.Owner, repo.Repo)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get project root dir
 	proot := c.config.Wd
 
-	croot := c.config.Index
-	if strings.HasSuffix(croot, ".md") {
-		croot = filepath.Dir(c.config.Index)
-	}
+	croot := c.indexDir()
 
 	var broot string
 	for _, d := range c.config.Badges {
@@ -232,20 +423,49 @@ func (c *Central) renderIndex(wr io.Writer) error {
 
 	badgesLinkRel, err := filepath.Rel(croot, broot)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	badgesURLRel, err := filepath.Rel(proot, broot)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return &IndexData{
+		Host:          host,
+		Repository:    c.config.Repository,
+		Reports:       c.visibleReports(),
+		History:       c.history,
+		Branches:      c.branches,
+		Summary:       c.summarize(),
+		Groups:        c.groups,
+		BadgesLinkRel: badgesLinkRel,
+		BadgesURLRel:  badgesURLRel,
+		RawRootURL:    rawRootURL,
+		Timestamp:     time.Now(),
+	}, nil
+}
+
+// indexDir returns the directory of the index file.
+func (c *Central) indexDir() string {
+	if strings.HasSuffix(c.config.Index, ".md") {
+		return filepath.Dir(c.config.Index)
 	}
+	return c.config.Index
+}
 
-	d := map[string]interface{}{
-		"Host":          host,
-		"Reports":       c.reports,
-		"BadgesLinkRel": badgesLinkRel,
-		"BadgesURLRel":  badgesURLRel,
-		"RawRootURL":    rawRootURL,
+func (c *Central) renderIndex(wr io.Writer, d *IndexData) error {
+	t := indexTmpl
+	if c.config.Template != "" {
+		b, err := os.ReadFile(c.config.Template)
+		if err != nil {
+			return err
+		}
+		t = b
+	}
+	tmpl, err := template.New("index").Funcs(c.funcs()).Parse(string(t))
+	if err != nil {
+		return err
 	}
 	if err := tmpl.Execute(wr, d); err != nil {
 		return err
@@ -253,23 +473,3 @@ func (c *Central) renderIndex(wr io.Writer) error {
 
 	return nil
 }
-
-func funcs() map[string]interface{} {
-	return template.FuncMap{
-		"coverage": func(r *report.Report) string {
-			return fmt.Sprintf("%.1f%%", r.CoveragePercent())
-		},
-		"ratio": func(r *report.Report) string {
-			if r.CodeToTestRatio == nil {
-				return "-"
-			}
-			return fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio())
-		},
-		"time": func(r *report.Report) string {
-			if r.TestExecutionTime == nil {
-				return "-"
-			}
-			return time.Duration(r.TestExecutionTimeNano()).String()
-		},
-	}
-}
@@ -2,38 +2,33 @@ package central
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"text/template"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/datastore"
 	"github.com/k1LoW/octocov/datastore/local"
 	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
 )
 
 func TestCollectReports(t *testing.T) {
-	c := config.New()
-	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
-	if err != nil {
-		t.Fatal(err)
-	}
-	bd, err := local.New(t.TempDir())
-	if err != nil {
-		t.Fatal(err)
-	}
-	ctr := New(&CentralConfig{
-		Repository:             "owner/repo",
-		Index:                  ".",
-		Wd:                     c.Getwd(),
-		Badges:                 []datastore.Datastore{bd},
-		Reports:                []datastore.Datastore{rd},
-		CoverageColor:          c.CoverageColor,
-		CodeToTestRatioColor:   c.CodeToTestRatioColor,
-		TestExecutionTimeColor: c.TestExecutionTimeColor,
-	})
+	ctr, _ := newTestCentral(t)
 
-	if err := ctr.collectReports(); err != nil {
+	if err := ctr.collectReports(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -41,30 +36,75 @@ func TestCollectReports(t *testing.T) {
 	if want := 5; len(got) != want {
 		t.Errorf("got %v\nwant %v", len(got), want)
 	}
+	if want := 2; len(ctr.history["k1LoW/tbls"]) != want {
+		t.Errorf("got %v\nwant %v", len(ctr.history["k1LoW/tbls"]), want)
+	}
 }
 
-func TestGenerateBadges(t *testing.T) {
-	c := config.New()
-	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
-	if err != nil {
-		t.Fatal(err)
+func TestCollectReportsConcurrently(t *testing.T) {
+	const (
+		repos   = 40
+		latency = 20 * time.Millisecond
+	)
+	fsys := fstest.MapFS{}
+	for i := 0; i < repos; i++ {
+		r := &report.Report{
+			Repository: fmt.Sprintf("owner/repo%d", i),
+			Ref:        "refs/heads/main",
+			Commit:     "1234567890abcdef",
+			Timestamp:  time.Now(),
+		}
+		fsys[fmt.Sprintf("owner/repo%d/report.json", i)] = &fstest.MapFile{Data: r.Bytes()}
 	}
-	td := t.TempDir()
-	bd, err := local.New(td)
-	if err != nil {
-		t.Fatal(err)
+	fsys["owner/invalid/report.json"] = &fstest.MapFile{Data: []byte("invalid")}
+
+	tests := []struct {
+		concurrency int
+		cancel      bool
+		wantErr     bool
+	}{
+		{1, false, false},
+		{8, false, false},
+		{8, true, true},
 	}
-	ctr := New(&CentralConfig{
-		Repository:             "owner/repo",
-		Index:                  ".",
-		Wd:                     c.Getwd(),
-		Badges:                 []datastore.Datastore{bd},
-		Reports:                []datastore.Datastore{rd},
-		CoverageColor:          c.CoverageColor,
-		CodeToTestRatioColor:   c.CodeToTestRatioColor,
-		TestExecutionTimeColor: c.TestExecutionTimeColor,
-	})
-	if err := ctr.collectReports(); err != nil {
+	elapsed := map[int]time.Duration{}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			Repository:  "owner/repo",
+			Reports:     []datastore.Datastore{&slowDatastore{fsys: &slowFS{fsys: fsys, latency: latency}}},
+			Concurrency: tt.concurrency,
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		if tt.cancel {
+			cancel()
+		}
+		start := time.Now()
+		err := ctr.collectReports(ctx)
+		cancel()
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Error("want error")
+			continue
+		}
+		elapsed[tt.concurrency] = time.Since(start)
+		if got := len(ctr.reports); got != repos {
+			t.Errorf("got %v\nwant %v", got, repos)
+		}
+	}
+	// reading files sequentially takes at least repos * latency
+	if elapsed[8] >= repos*latency {
+		t.Errorf("collecting reports with 8 workers took %s ( 1 worker: %s )", elapsed[8], elapsed[1])
+	}
+}
+
+func TestGenerateBadges(t *testing.T) {
+	ctr, td := newTestCentral(t)
+	if err := ctr.collectReports(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -95,6 +135,778 @@ func TestGenerateBadges(t *testing.T) {
 	}
 }
 
+func TestGenerateBadgesIncrementally(t *testing.T) {
+	bd, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bd.Put(context.Background(), "owner/removed/coverage.svg", []byte("<svg></svg>")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		groups        []*Group
+		wantPaths     int
+		wantUpdated   int
+		wantUnchanged int
+		wantRemoved   []string
+	}{
+		{nil, 10, 5, 0, []string{"owner/removed"}},
+		{nil, 0, 0, 5, []string{"owner/removed"}},
+		{[]*Group{{Name: "k1low", Repositories: []string{"k1LoW/*"}}}, 0, 0, 5, []string{"owner/removed"}},
+		// the badge of the group generated by the previous run is not a removed repository
+		{[]*Group{{Name: "k1low", Repositories: []string{"k1LoW/*"}}}, 0, 0, 5, []string{"owner/removed"}},
+	}
+	for _, tt := range tests {
+		// the badges datastore is shared between the runs
+		ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+			cfg.Badges = []datastore.Datastore{bd}
+			cfg.Groups = tt.groups
+		})
+		ctr.previous = ctr.badgedRepositories()
+		if err := ctr.collectReports(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		paths, err := ctr.generateBadges()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctr.groups = ctr.groupReports(context.Background())
+		if _, err := ctr.generateGroupBadges(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(paths); got != tt.wantPaths {
+			t.Errorf("got %v\nwant %v", got, tt.wantPaths)
+		}
+		changes := ctr.Changes()
+		if got := len(changes.Updated); got != tt.wantUpdated {
+			t.Errorf("got %v\nwant %v", got, tt.wantUpdated)
+		}
+		if got := len(changes.Unchanged); got != tt.wantUnchanged {
+			t.Errorf("got %v\nwant %v", got, tt.wantUnchanged)
+		}
+		if diff := cmp.Diff(changes.Removed, tt.wantRemoved, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestRemoveRepositories(t *testing.T) {
+	html := t.TempDir()
+	ctr, td := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.HTML = html
+	})
+	ctx := context.Background()
+	for _, p := range []string{
+		filepath.Join(td, "owner/removed/coverage.svg"),
+		filepath.Join(td, "owner/removed/branches/main/coverage.svg"),
+		filepath.Join(html, "badges/owner/removed/coverage.svg"),
+		filepath.Join(html, "repos/owner/removed/index.html"),
+	} {
+		if err := writeFile(p, []byte("removed")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctr.previous = ctr.badgedRepositories()
+	if err := ctr.collectReports(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctr.generateBadges(); err != nil {
+		t.Fatal(err)
+	}
+	removed := ctr.removedRepositories()
+	if diff := cmp.Diff(removed, []string{"owner/removed"}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	paths, err := ctr.removeRepositories(ctx, removed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(paths), 4; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should be deleted", p)
+		}
+	}
+	for _, r := range ctr.reports {
+		if _, err := os.Stat(filepath.Join(td, r.Repository, "coverage.svg")); err != nil {
+			t.Errorf("the badge of %s should not be deleted: %v", r.Repository, err)
+		}
+	}
+}
+
+func TestGenerateBadgesWithFormats(t *testing.T) {
+	tests := []struct {
+		formats []string
+		trend   bool
+		want    int
+	}{
+		{nil, false, 10},
+		{[]string{"svg"}, false, 10},
+		{[]string{"png"}, false, 20},
+		{[]string{"svg", "png"}, false, 30},
+		{[]string{"svg", "json"}, false, 20},
+		// trend badges are not generated as JSON
+		{[]string{"json"}, true, 10},
+	}
+	for _, tt := range tests {
+		ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+			cfg.BadgeFormats = tt.formats
+			cfg.BadgeTrend = tt.trend
+		})
+		if err := ctr.collectReports(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		paths, err := ctr.generateBadges()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != tt.want {
+			t.Errorf("got %v\nwant %v", len(paths), tt.want)
+		}
+	}
+}
+
+func TestGenerateTrendBadges(t *testing.T) {
+	ctr, td := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.BadgeTrend = true
+		cfg.BadgeTrendLimit = 10
+	})
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctr.generateBadges(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path          string
+		wantSparkline bool
+	}{
+		{"k1LoW/tbls/coverage-trend.svg", true},
+		{"winebarrel/ridgepole/coverage-trend.svg", false},
+	}
+	for _, tt := range tests {
+		b, err := os.ReadFile(filepath.Join(td, tt.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bytes.Contains(b, []byte("<polyline")); got != tt.wantSparkline {
+			t.Errorf("%s: got %v\nwant %v", tt.path, got, tt.wantSparkline)
+		}
+	}
+}
+
+func TestGenerateHTML(t *testing.T) {
+	hd := t.TempDir()
+	ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.HTML = hd
+	})
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctr.generateBadges(); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := ctr.generateHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10 badges, index and 5 pages of repositories
+	if want := 16; len(paths) != want {
+		t.Errorf("got %v\nwant %v", len(paths), want)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"index.html", `<a href="repos/k1LoW/tbls/index.html">k1LoW/tbls</a>`},
+		{"index.html", `<img src="badges/k1LoW/tbls/coverage.svg" alt="Coverage">`},
+		{"repos/k1LoW/tbls/index.html", `<img src="../../../badges/k1LoW/tbls/coverage.svg" alt="Coverage">`},
+		{"repos/k1LoW/tbls/index.html", `<h2>Files</h2>`},
+	}
+	for _, tt := range tests {
+		b, err := os.ReadFile(filepath.Join(hd, tt.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(b, []byte(tt.want)) {
+			t.Errorf("%s does not contain %s", tt.path, tt.want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(hd, "badges", "k1LoW", "tbls", "coverage.svg")); err != nil {
+		t.Error(err)
+	}
+
+	// nothing is changed
+	paths, err = ctr.generateHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0; len(paths) != want {
+		t.Errorf("got %v\nwant %v", len(paths), want)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	ctr, _ := newTestCentral(t)
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{`{{ range .Reports }}{{ if eq .Repository "k1LoW/tbls" }}{{ coverage . }} {{ ratio . }} {{ time . }}{{ end }}{{ end }}`, "68.5% 1:0.5 4m40s", false},
+		{`{{ percent 12.345 }}`, "12.3%", false},
+		{`{{ range .Reports }}{{ if eq .Repository "k1LoW/tbls" }}{{ trend "coverage" . }}{{ end }}{{ end }}`, "▅▅", false},
+		{`{{ range .Reports }}{{ if eq .Repository "winebarrel/ridgepole" }}{{ trend "coverage" . }} {{ trend "time" . }}{{ end }}{{ end }}`, "▅ -", false},
+		{`{{ range .Reports }}{{ if eq .Repository "k1LoW/tbls" }}{{ color "coverage" . }}{{ end }}{{ end }}`, ctr.config.CoverageColor(68.49842492124606), false},
+		{`{{ range .Reports }}{{ color "branch" . }}{{ end }}`, "", true},
+	}
+	for _, tt := range tests {
+		tmpl, err := template.New("index").Funcs(ctr.funcs()).Parse(tt.tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := new(bytes.Buffer)
+		err = tmpl.Execute(buf, &IndexData{Reports: ctr.reports, History: ctr.history})
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Hour, "just now"},
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{59 * time.Minute, "59 minutes ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{48 * time.Hour, "2 days ago"},
+		{90 * 24 * time.Hour, "3 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+	}
+	for _, tt := range tests {
+		if got := relativeTime(now.Add(-tt.d), now); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestGenerateHistory(t *testing.T) {
+	ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.History = true
+	})
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ctr.generateHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"history.json", "coverage-chart.svg", "ratio-chart.svg", "time-chart.svg"} {
+		if _, ok := files[filepath.Join("k1LoW/tbls", p)]; !ok {
+			t.Errorf("%s is not generated", p)
+		}
+	}
+	if _, ok := files[filepath.Join("winebarrel/ridgepole", "time-chart.svg")]; ok {
+		t.Error("time-chart.svg of the repository without test execution time is generated")
+	}
+	entries := []historyEntry{}
+	if err := json.Unmarshal(files[filepath.Join("k1LoW/tbls", "history.json")], &entries); err != nil {
+		t.Fatal(err)
+	}
+	if want := 2; len(entries) != want {
+		t.Errorf("got %v\nwant %v", len(entries), want)
+	}
+	if entries[0].Timestamp.After(entries[1].Timestamp) {
+		t.Errorf("entries are not sorted by timestamp: %v", entries)
+	}
+	// the older report has only coverage
+	if entries[0].Coverage == nil || entries[0].CodeToTestRatio != nil || entries[0].TestExecutionTime != nil {
+		t.Errorf("got %v\nwant only coverage", entries[0])
+	}
+	if entries[1].Coverage == nil || entries[1].CodeToTestRatio == nil || entries[1].TestExecutionTime == nil {
+		t.Errorf("got %v\nwant all metrics", entries[1])
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	d := float64(90 * time.Second)
+	reports := []*report.Report{
+		{Repository: "owner/a", Coverage: &coverage.Coverage{Covered: 90, Total: 100}, TestExecutionTime: &d},
+		{Repository: "owner/b", Coverage: &coverage.Coverage{Covered: 10, Total: 100}, TestExecutionTime: &d},
+		{Repository: "owner/c", Coverage: &coverage.Coverage{Covered: 600, Total: 800}},
+		{Repository: "owner/d"},
+	}
+	got := summarize(reports, []float64{50, 80})
+	if want := 4; got.Repositories != want {
+		t.Errorf("got %v\nwant %v", got.Repositories, want)
+	}
+	if want := 70.0; got.Coverage != want {
+		t.Errorf("got %v\nwant %v", got.Coverage, want)
+	}
+	if want := 75.0; got.CoverageMedian != want {
+		t.Errorf("got %v\nwant %v", got.CoverageMedian, want)
+	}
+	if want := []int{1, 2}; got.Below[0].Repositories != want[0] || got.Below[1].Repositories != want[1] {
+		t.Errorf("got %v, %v\nwant %v", got.Below[0].Repositories, got.Below[1].Repositories, want)
+	}
+	if want := float64(3 * time.Minute); got.TestExecutionTime != want {
+		t.Errorf("got %v\nwant %v", got.TestExecutionTime, want)
+	}
+}
+
+func TestGenerateSummaryBadges(t *testing.T) {
+	ctr, td := newTestCentral(t)
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctr.generateBadges(); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := ctr.generateSummaryBadges(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// k1LoW, sebastianbergmann, tiangolo and winebarrel
+	if want := 4; len(paths) != want {
+		t.Errorf("got %v\nwant %v", len(paths), want)
+	}
+	if _, err := os.Stat(filepath.Join(td, "k1LoW", "coverage.svg")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStale(t *testing.T) {
+	tests := []struct {
+		staleAfter  time.Duration
+		hideStale   bool
+		wantStale   int
+		wantVisible int
+		wantColor   bool
+	}{
+		{0, true, 0, 5, false},
+		{100 * 365 * 24 * time.Hour, true, 0, 5, false},
+		{24 * time.Hour, false, 5, 5, true},
+		{24 * time.Hour, true, 5, 0, true},
+	}
+	for _, tt := range tests {
+		ctr, td := newTestCentral(t, func(cfg *CentralConfig) {
+			cfg.StaleAfter = tt.staleAfter
+			cfg.HideStale = tt.hideStale
+		})
+		if err := ctr.collectReports(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(ctr.StaleRepositories()); got != tt.wantStale {
+			t.Errorf("got %v\nwant %v", got, tt.wantStale)
+		}
+		if got := len(ctr.visibleReports()); got != tt.wantVisible {
+			t.Errorf("got %v\nwant %v", got, tt.wantVisible)
+		}
+		if _, err := ctr.generateBadges(); err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join(td, "k1LoW", "tbls", "coverage.svg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bytes.Contains(b, []byte(staleColor)); got != tt.wantColor {
+			t.Errorf("got %v\nwant %v", got, tt.wantColor)
+		}
+	}
+}
+
+func TestRenderIndexStale(t *testing.T) {
+	ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.StaleAfter = 24 * time.Hour
+	})
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	d := &IndexData{
+		Host:       "https://github.com",
+		Repository: "owner/repo",
+		Reports:    ctr.visibleReports(),
+		Summary:    ctr.summarize(),
+	}
+	buf := &bytes.Buffer{}
+	if err := ctr.renderIndex(buf, d); err != nil {
+		t.Fatal(err)
+	}
+	// the index should not be changed day by day while the report is stale
+	if want := "[k1LoW/tbls](https://github.com/k1LoW/tbls) ( stale: last reported 2021-09-11 )"; !strings.Contains(buf.String(), want) {
+		t.Errorf("got %v\nwant %v", buf.String(), want)
+	}
+}
+
+func TestBranches(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testdataDir(t), "reports", "k1LoW", "tbls", "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rd, "k1LoW", "tbls"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i, ref := range []string{"refs/heads/history", "refs/heads/main", "refs/heads/release/1.0", "refs/heads/feature", "refs/pull/1/merge"} {
+		r := &report.Report{}
+		if err := json.Unmarshal(b, r); err != nil {
+			t.Fatal(err)
+		}
+		r.Ref = ref
+		r.Timestamp = r.Timestamp.Add(time.Duration(i) * time.Hour)
+		out, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(rd, "k1LoW", "tbls", fmt.Sprintf("report%d.json", i)), out, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		defaultBranch string
+		tracked       []string
+		wantRef       string
+		wantBadges    []string
+		wantNoBadges  []string
+	}{
+		{"", nil, "refs/heads/feature", []string{"branches/feature/coverage.svg"}, []string{"branches/main/coverage.svg", "branches/release/1.0/coverage.svg"}},
+		{"main", nil, "refs/heads/main", []string{"branches/main/coverage.svg"}, []string{"branches/feature/coverage.svg", "branches/release/1.0/coverage.svg"}},
+		{"main", []string{"release/*"}, "refs/heads/main", []string{"branches/main/coverage.svg", "branches/release/1.0/coverage.svg"}, []string{"branches/feature/coverage.svg"}},
+		{"develop", nil, "refs/heads/feature", []string{"branches/feature/coverage.svg"}, []string{"branches/main/coverage.svg"}},
+		{"main", []string{"history"}, "refs/heads/main", []string{"branches/history/coverage.svg"}, []string{"history"}},
+	}
+	for _, tt := range tests {
+		rds, err := local.New(rd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var defaultBranch func(ctx context.Context, repository string) (string, error)
+		if tt.defaultBranch != "" {
+			defaultBranch = func(ctx context.Context, repository string) (string, error) {
+				return tt.defaultBranch, nil
+			}
+		}
+		ctr, td := newTestCentral(t, func(cfg *CentralConfig) {
+			cfg.Reports = []datastore.Datastore{rds}
+			cfg.DefaultBranch = defaultBranch
+			cfg.BranchBadges = true
+			cfg.TrackedBranches = tt.tracked
+		})
+		if err := ctr.collectReports(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(ctr.reports); got != 1 {
+			t.Fatalf("got %v\nwant %v", got, 1)
+		}
+		if got := ctr.reports[0].Ref; got != tt.wantRef {
+			t.Errorf("got %v\nwant %v", got, tt.wantRef)
+		}
+		if got := len(ctr.branches["k1LoW/tbls"]); got != 4 {
+			t.Errorf("got %v\nwant %v", got, 4)
+		}
+		if got := len(ctr.history["k1LoW/tbls"]); got != 1 {
+			t.Errorf("got %v\nwant %v", got, 1)
+		}
+		if _, err := ctr.generateBadges(); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range tt.wantBadges {
+			if _, err := os.Stat(filepath.Join(td, "k1LoW", "tbls", p)); err != nil {
+				t.Errorf("%s: %v", p, err)
+			}
+		}
+		for _, p := range tt.wantNoBadges {
+			if _, err := os.Stat(filepath.Join(td, "k1LoW", "tbls", p)); err == nil {
+				t.Errorf("%s: should not be generated", p)
+			}
+		}
+	}
+}
+
+func TestDefaultBranches(t *testing.T) {
+	const latency = 20 * time.Millisecond
+	repos := []string{}
+	for i := 0; i < 16; i++ {
+		repos = append(repos, fmt.Sprintf("owner/repo%d", i))
+	}
+	repos = append(repos, "owner/notfound")
+	ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.Concurrency = 8
+		cfg.DefaultBranch = func(ctx context.Context, repository string) (string, error) {
+			time.Sleep(latency)
+			if repository == "owner/notfound" {
+				return "", errors.New("not found")
+			}
+			return "main", nil
+		}
+	})
+	start := time.Now()
+	got := ctr.defaultBranches(context.Background(), repos)
+	// getting the default branches sequentially takes at least len(repos) * latency
+	if elapsed := time.Since(start); elapsed >= time.Duration(len(repos))*latency {
+		t.Errorf("getting the default branches with 8 workers took %s", elapsed)
+	}
+	if want := len(repos) - 1; len(got) != want {
+		t.Errorf("got %v\nwant %v", len(got), want)
+	}
+	if _, ok := got["owner/notfound"]; ok {
+		t.Error("the default branch of owner/notfound should not be got")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	hd := t.TempDir()
+	ctr, td := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.HTML = hd
+		cfg.Groups = []*Group{
+			{Name: "k1low", Repositories: []string{"k1LoW/*"}},
+			{Name: "python", Topic: "python"},
+			{Name: "empty", Repositories: []string{"owner/*"}},
+		}
+		cfg.Topics = func(ctx context.Context, repository string) ([]string, error) {
+			if repository == "tiangolo/fastapi" {
+				return []string{"python", "api"}, nil
+			}
+			return []string{}, nil
+		}
+	})
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctr.generateBadges(); err != nil {
+		t.Fatal(err)
+	}
+	ctr.groups = ctr.groupReports(context.Background())
+	if _, err := ctr.generateGroupBadges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		want      int
+		wantBadge bool
+	}{
+		{"k1low", 2, true},
+		{"python", 1, true},
+		{"empty", 0, false},
+	}
+	if got := len(ctr.groups); got != len(tests) {
+		t.Fatalf("got %v\nwant %v", got, len(tests))
+	}
+	for i, tt := range tests {
+		g := ctr.groups[i]
+		if g.Name != tt.name {
+			t.Errorf("got %v\nwant %v", g.Name, tt.name)
+		}
+		if got := len(g.Reports); got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.name, got, tt.want)
+		}
+		if got := g.Summary.Repositories; got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.name, got, tt.want)
+		}
+		_, err := os.Stat(filepath.Join(td, "@groups", tt.name, "coverage.svg"))
+		if got := err == nil; got != tt.wantBadge {
+			t.Errorf("%s: got %v\nwant %v", tt.name, got, tt.wantBadge)
+		}
+	}
+
+	if _, err := ctr.generateHTML(); err != nil {
+		t.Fatal(err)
+	}
+	htests := []struct {
+		path string
+		want string
+	}{
+		{"index.html", `<a href="groups/k1low/index.html">k1low</a>`},
+		{"groups/k1low/index.html", `<h1>k1low</h1>`},
+		{"groups/k1low/index.html", `<img src="../../badges/@groups/k1low/coverage.svg" alt="Coverage">`},
+		{"groups/k1low/index.html", `<a href="../../repos/k1LoW/tbls/index.html">k1LoW/tbls</a>`},
+		{"groups/python/index.html", `<a href="../../repos/tiangolo/fastapi/index.html">tiangolo/fastapi</a>`},
+	}
+	for _, tt := range htests {
+		b, err := os.ReadFile(filepath.Join(hd, tt.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(b, []byte(tt.want)) {
+			t.Errorf("%s does not contain %s", tt.path, tt.want)
+		}
+	}
+}
+
+func TestRenderIndexJSON(t *testing.T) {
+	ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+		cfg.Repository = "owner/central"
+	})
+	if err := ctr.collectReports(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctr.generateBadges(); err != nil {
+		t.Fatal(err)
+	}
+	d := &IndexData{
+		Host:         "https://github.com",
+		Repository:   "owner/central",
+		Reports:      ctr.visibleReports(),
+		Summary:      ctr.summarize(),
+		BadgesURLRel: "badges",
+		RawRootURL:   "https://raw.githubusercontent.com/owner/central/main",
+	}
+	buf := &bytes.Buffer{}
+	if err := ctr.renderIndexJSON(buf, d); err != nil {
+		t.Fatal(err)
+	}
+	got := &IndexJSON{}
+	if err := json.Unmarshal(buf.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SchemaVersion != IndexJSONSchemaVersion {
+		t.Errorf("got %v\nwant %v", got.SchemaVersion, IndexJSONSchemaVersion)
+	}
+	if want := 5; len(got.Repositories) != want {
+		t.Fatalf("got %v\nwant %v", len(got.Repositories), want)
+	}
+	if want := 5; got.Summary.Repositories != want {
+		t.Errorf("got %v\nwant %v", got.Summary.Repositories, want)
+	}
+	tbls := got.Repositories[1]
+	if want := "k1LoW/tbls"; tbls.Repository != want {
+		t.Errorf("got %v\nwant %v", tbls.Repository, want)
+	}
+	if tbls.Coverage == nil || tbls.CodeToTestRatio == nil || tbls.TestExecutionTime == nil {
+		t.Errorf("got %v\nwant all metrics", tbls)
+	}
+	if want := "https://raw.githubusercontent.com/owner/central/main/badges/k1LoW/tbls/coverage.svg"; tbls.Badges["coverage"] != want {
+		t.Errorf("got %v\nwant %v", tbls.Badges["coverage"], want)
+	}
+	fastapi := got.Repositories[3]
+	if fastapi.CodeToTestRatio != nil {
+		t.Errorf("got %v\nwant %v", fastapi.CodeToTestRatio, nil)
+	}
+	if _, ok := fastapi.Badges["ratio"]; ok {
+		t.Error("ratio badge of tiangolo/fastapi should not exist")
+	}
+}
+
+func TestRenderFeed(t *testing.T) {
+	rd, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := &report.Report{}
+	if err := prev.Load(filepath.Join(testdataDir(t), "reports", "k1LoW", "tbls", "report.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rd.Put(context.Background(), "k1LoW/tbls/history/1.json", prev.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	cur := &report.Report{}
+	if err := json.Unmarshal(prev.Bytes(), cur); err != nil {
+		t.Fatal(err)
+	}
+	cur.Commit = "1234567890abcdef1234567890abcdef12345678"
+	cur.Timestamp = prev.Timestamp.Add(24 * time.Hour)
+	cur.Coverage.Covered = cur.Coverage.Covered - cur.Coverage.Total/10
+	if err := rd.Put(context.Background(), "k1LoW/tbls/history/2.json", cur.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	// the latest report is not changed significantly, but the older change should be in the feed
+	latest := &report.Report{}
+	if err := json.Unmarshal(cur.Bytes(), latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Commit = "abcdef1234567890abcdef1234567890abcdef12"
+	latest.Timestamp = cur.Timestamp.Add(24 * time.Hour)
+	latest.Coverage.Covered = latest.Coverage.Covered + 1
+	if err := rd.Put(context.Background(), "k1LoW/tbls/report.json", latest.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	phpunit, err := os.ReadFile(filepath.Join(testdataDir(t), "reports", "sebastianbergmann", "phpunit", "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rd.Put(context.Background(), "sebastianbergmann/phpunit/report.json", phpunit); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		thresholds  map[string]float64
+		wantEntries int
+	}{
+		{map[string]float64{"coverage": 2}, 1},
+		{map[string]float64{"coverage": 20}, 0},
+		{map[string]float64{"ratio": 0, "time": 0}, 0},
+	}
+	for _, tt := range tests {
+		ctr, _ := newTestCentral(t, func(cfg *CentralConfig) {
+			cfg.Repository = "owner/central"
+			cfg.Reports = []datastore.Datastore{rd}
+			cfg.Feed = true
+			cfg.FeedThresholds = tt.thresholds
+		})
+		if err := ctr.collectReports(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		d := &IndexData{
+			Host:       "https://github.com",
+			Repository: "owner/central",
+			Reports:    ctr.visibleReports(),
+		}
+		buf := &bytes.Buffer{}
+		if err := ctr.renderFeed(buf, d); err != nil {
+			t.Fatal(err)
+		}
+		got := &atomFeed{}
+		if err := xml.Unmarshal(buf.Bytes(), got); err != nil {
+			t.Fatal(err)
+		}
+		if want := "https://github.com/owner/central"; got.ID != want {
+			t.Errorf("got %v\nwant %v", got.ID, want)
+		}
+		if len(got.Entries) != tt.wantEntries {
+			t.Fatalf("got %v\nwant %v", len(got.Entries), tt.wantEntries)
+		}
+		if tt.wantEntries == 0 {
+			continue
+		}
+		e := got.Entries[0]
+		if want := fmt.Sprintf("https://github.com/k1LoW/tbls/commit/1234567890abcdef1234567890abcdef12345678#%d", cur.Timestamp.Unix()); e.ID != want {
+			t.Errorf("got %v\nwant %v", e.ID, want)
+		}
+		if want := fmt.Sprintf("k1LoW/tbls: coverage %.1f%% (%+.1f%%)", cur.CoveragePercent(), cur.CoveragePercent()-prev.CoveragePercent()); e.Title != want {
+			t.Errorf("got %v\nwant %v", e.Title, want)
+		}
+		if want := "https://github.com/k1LoW/tbls"; len(e.Links) != 2 || e.Links[1].Href != want {
+			t.Errorf("got %v\nwant %v", e.Links, want)
+		}
+		if !strings.Contains(e.Content.Body, "<td>Coverage</td><td>68.5%</td><td>58.5%</td><td>-10.0%</td>") {
+			t.Errorf("got %v\nwant the diff table", e.Content.Body)
+		}
+	}
+}
+
 func TestRenderIndex(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -131,12 +943,16 @@ func TestRenderIndex(t *testing.T) {
 		CodeToTestRatioColor:   c.CodeToTestRatioColor,
 		TestExecutionTimeColor: c.TestExecutionTimeColor,
 	})
-	if err := ctr.collectReports(); err != nil {
+	if err := ctr.collectReports(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
+	d, err := ctr.indexData()
+	if err != nil {
+		t.Fatal(err)
+	}
 	buf := &bytes.Buffer{}
-	if err := ctr.renderIndex(buf); err != nil {
+	if err := ctr.renderIndex(buf, d); err != nil {
 		t.Fatal(err)
 	}
 
@@ -152,6 +968,69 @@ func TestRenderIndex(t *testing.T) {
 	}
 }
 
+// slowDatastore is the datastore of the fs.FS for testing.
+type slowDatastore struct {
+	fsys fs.FS
+}
+
+func (d *slowDatastore) Put(ctx context.Context, path string, content []byte) error {
+	return errors.New("not implemented")
+}
+
+func (d *slowDatastore) Delete(ctx context.Context, paths []string) error {
+	return errors.New("not implemented")
+}
+
+func (d *slowDatastore) StoreReport(ctx context.Context, r *report.Report) error {
+	return errors.New("not implemented")
+}
+
+func (d *slowDatastore) FS() (fs.FS, error) {
+	return d.fsys, nil
+}
+
+// slowFS is the fs.FS that adds latency to opening JSON files, like remote datastores.
+type slowFS struct {
+	fsys    fs.FS
+	latency time.Duration
+}
+
+func (f *slowFS) Open(name string) (fs.File, error) {
+	if strings.HasSuffix(name, ".json") {
+		time.Sleep(f.latency)
+	}
+	return f.fsys.Open(name)
+}
+
+// newTestCentral returns the Central that collects the reports in testdata/reports and puts the badges into the returned directory.
+func newTestCentral(t *testing.T, opts ...func(cfg *CentralConfig)) (*Central, string) {
+	t.Helper()
+	c := config.New()
+	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := t.TempDir()
+	bd, err := local.New(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &CentralConfig{
+		Repository:             "owner/repo",
+		Index:                  ".",
+		Wd:                     c.Getwd(),
+		Badges:                 []datastore.Datastore{bd},
+		Reports:                []datastore.Datastore{rd},
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return New(cfg), td
+}
+
 func testdataDir(t *testing.T) string {
 	t.Helper()
 	wd, err := os.Getwd()
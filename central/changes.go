@@ -0,0 +1,182 @@
+package central
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/octocov/datastore/local"
+)
+
+// Changes is the repositories whose badges are changed by Generate.
+type Changes struct {
+	Updated   []string
+	Unchanged []string
+	Removed   []string
+}
+
+func contentHash(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// isUnchanged returns true if the file in fsys has the same content hash.
+func isUnchanged(fsys fs.FS, p string, content []byte) bool {
+	if fsys == nil {
+		return false
+	}
+	b, err := fs.ReadFile(fsys, filepath.ToSlash(p))
+	if err != nil {
+		return false
+	}
+	return contentHash(b) == contentHash(content)
+}
+
+// writeFileIfChanged writes the file only if the content is changed, and returns true if it is written.
+func writeFileIfChanged(p string, b []byte) (bool, error) {
+	current, err := os.ReadFile(p)
+	if err == nil && contentHash(current) == contentHash(b) {
+		return false, nil
+	}
+	if err := writeFile(p, b); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// badgedRepositories returns the directories that have the coverage badge in the badges datastores.
+func (c *Central) badgedRepositories() map[string]struct{} {
+	repos := map[string]struct{}{}
+	for _, d := range c.config.Badges {
+		fsys, err := d.FS()
+		if err != nil {
+			continue
+		}
+		_ = fs.WalkDir(fsys, ".", func(p string, e fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if e.IsDir() || strings.TrimSuffix(e.Name(), path.Ext(e.Name())) != "coverage" {
+				return nil
+			}
+			repos[path.Dir(p)] = struct{}{}
+			return nil
+		})
+	}
+	return repos
+}
+
+// Changes returns the repositories whose badges are updated or unchanged, and the repositories removed since the last generation.
+func (c *Central) Changes() *Changes {
+	ch := &Changes{
+		Updated:   []string{},
+		Unchanged: []string{},
+		Removed:   []string{},
+	}
+	for _, r := range c.reports {
+		updated := false
+		for p := range c.changed {
+			if strings.HasPrefix(filepath.ToSlash(p), r.Repository+"/") {
+				updated = true
+				break
+			}
+		}
+		if updated {
+			ch.Updated = append(ch.Updated, r.Repository)
+		} else {
+			ch.Unchanged = append(ch.Unchanged, r.Repository)
+		}
+	}
+	ch.Removed = c.removedRepositories()
+	return ch
+}
+
+// removedRepositories returns the repositories that have the badges generated by the previous run but are no longer reported.
+func (c *Central) removedRepositories() []string {
+	current := map[string]struct{}{}
+	for _, r := range c.reports {
+		current[r.Repository] = struct{}{}
+	}
+	removed := []string{}
+	for repo := range c.previous {
+		if _, ok := current[repo]; ok {
+			continue
+		}
+		if strings.Count(repo, "/") != 1 || strings.HasPrefix(repo, groupBadgesDir+"/") {
+			// badges of owners, groups and branches
+			continue
+		}
+		removed = append(removed, repo)
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// removeRepositories deletes the badges and HTML pages of the repositories, and returns the paths of the deleted local files.
+func (c *Central) removeRepositories(ctx context.Context, repos []string) ([]string, error) {
+	deletedPaths := []string{}
+	for _, d := range c.config.Badges {
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, err
+		}
+		files := []string{}
+		for _, repo := range repos {
+			if err := fs.WalkDir(fsys, repo, func(p string, e fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !e.IsDir() {
+					files = append(files, p)
+				}
+				return nil
+			}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if err := d.Delete(ctx, files); err != nil {
+			return nil, err
+		}
+		switch v := d.(type) {
+		case *local.Local:
+			for _, p := range files {
+				deletedPaths = append(deletedPaths, filepath.Join(v.Root(), p))
+			}
+		}
+	}
+	if c.config.HTML == "" {
+		return deletedPaths, nil
+	}
+	for _, repo := range repos {
+		for _, dir := range []string{filepath.Join(c.config.HTML, "badges", repo), filepath.Join(c.config.HTML, "repos", repo)} {
+			if err := filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !e.IsDir() {
+					deletedPaths = append(deletedPaths, p)
+				}
+				return nil
+			}); err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return deletedPaths, nil
+}
@@ -0,0 +1,188 @@
+package central
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const defaultConcurrency = 8
+
+type reportFile struct {
+	fsys fs.FS
+	path string
+}
+
+// collectReports collects the reports from all datastores, and selects the report of each repository.
+func (c *Central) collectReports(ctx context.Context) error {
+	start := time.Now()
+	rs, files, err := c.readReports(ctx)
+	if err != nil {
+		return err
+	}
+
+	all := map[string][]*report.Report{}
+	seen := map[string]struct{}{}
+	for _, r := range rs {
+		// report.json is also kept as history
+		k := fmt.Sprintf("%s-%s-%d", r.Repository, r.Commit, r.Timestamp.UnixNano())
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		all[r.Repository] = append(all[r.Repository], r)
+	}
+
+	repos := []string{}
+	for repo := range all {
+		repos = append(repos, repo)
+	}
+	defaultBranches := c.defaultBranches(ctx, repos)
+
+	c.reports = []*report.Report{}
+	c.history = map[string][]*report.Report{}
+	c.branches = map[string]map[string]*report.Report{}
+	for repo, rs := range all {
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].Timestamp.Before(rs[j].Timestamp) })
+		c.branches[repo] = latestReportsByBranch(rs)
+		r := c.selectReport(repo, defaultBranches[repo], rs)
+		c.reports = append(c.reports, r)
+		// history of the ref of the selected report
+		for _, rr := range rs {
+			if rr.Ref == r.Ref {
+				c.history[repo] = append(c.history[repo], rr)
+			}
+		}
+	}
+	sort.Slice(c.reports, func(i, j int) bool { return c.reports[i].Repository < c.reports[j].Repository })
+	for _, r := range c.reports {
+		_, _ = fmt.Fprintf(os.Stderr, "Collect report of %s\n", r.Repository)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Collected %d reports of %d repositories from %d files in %s\n", len(seen), len(c.reports), files, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// concurrency returns the number of workers to access the datastores and GitHub API.
+func (c *Central) concurrency() int {
+	if c.config.Concurrency <= 0 {
+		return defaultConcurrency
+	}
+	return c.config.Concurrency
+}
+
+// readReports walks the datastores in parallel and reads the report files with the worker pool of Concurrency workers.
+// It returns the reports and the number of files read.
+func (c *Central) readReports(ctx context.Context) ([]*report.Report, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	concurrency := c.concurrency()
+
+	var (
+		once sync.Once
+		rerr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			rerr = err
+			cancel()
+		})
+	}
+
+	// walk datastores
+	files := make(chan reportFile)
+	walkers := &sync.WaitGroup{}
+	for _, d := range c.config.Reports {
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, 0, err
+		}
+		walkers.Add(1)
+		go func(fsys fs.FS) {
+			defer walkers.Done()
+			if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+					return nil
+				}
+				select {
+				case files <- reportFile{fsys: fsys, path: path}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				return nil
+			}); err != nil {
+				fail(err)
+			}
+		}(fsys)
+	}
+	go func() {
+		walkers.Wait()
+		close(files)
+	}()
+
+	// read and decode files
+	results := make(chan *report.Report)
+	workers := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for f := range files {
+				r := readReport(f)
+				select {
+				case results <- r:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	reports := []*report.Report{}
+	n := 0
+	for r := range results {
+		n++
+		if r != nil {
+			reports = append(reports, r)
+		}
+	}
+	if rerr != nil {
+		return nil, 0, rerr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	return reports, n, nil
+}
+
+// readReport reads the report file. It returns nil if the file is not a report.
+func readReport(f reportFile) *report.Report {
+	file, err := f.fsys.Open(f.path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return nil
+	}
+	r := &report.Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil
+	}
+	return r
+}
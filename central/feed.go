@@ -0,0 +1,252 @@
+package central
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+// feedMetrics is the metrics compared in the feed, in order.
+var feedMetrics = []string{"coverage", "ratio", "time"}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"feed"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []*atomLink  `xml:"link"`
+	Author  *atomAuthor  `xml:"author"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []*atomLink  `xml:"link"`
+	Content *atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// FeedChange is the significant change of the metric between the previous report and the report.
+type FeedChange struct {
+	Metric   string
+	Previous float64
+	Current  float64
+}
+
+// FeedEntry is the significant changes of the report from the previous report of the repository.
+type FeedEntry struct {
+	Previous *report.Report
+	Current  *report.Report
+	Changes  []*FeedChange
+}
+
+// feedLimit is the maximum number of the entries of the feed.
+const feedLimit = 50
+
+// feedEntries compares each pair of consecutive reports in the history of each repository and returns the newest feedLimit significant changes.
+func (c *Central) feedEntries() ([]*FeedEntry, error) {
+	entries := []*FeedEntry{}
+	for _, r := range c.visibleReports() {
+		rs := c.history[r.Repository]
+		for i := 1; i < len(rs); i++ {
+			e, err := c.feedEntry(rs[i-1], rs[i])
+			if err != nil {
+				return nil, err
+			}
+			if e == nil {
+				continue
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Current.Timestamp.Equal(entries[j].Current.Timestamp) {
+			return entries[i].Current.Repository < entries[j].Current.Repository
+		}
+		return entries[i].Current.Timestamp.After(entries[j].Current.Timestamp)
+	})
+	if len(entries) > feedLimit {
+		entries = entries[:feedLimit]
+	}
+	return entries, nil
+}
+
+// feedEntry compares the report with the previous one and returns the significant changes ( nil if there are none ).
+func (c *Central) feedEntry(prev, r *report.Report) (*FeedEntry, error) {
+	e := &FeedEntry{
+		Previous: prev,
+		Current:  r,
+	}
+	for _, metric := range feedMetrics {
+		threshold, ok := c.config.FeedThresholds[metric]
+		if !ok {
+			continue
+		}
+		a, ok, err := metricValue(metric, prev)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		b, ok, err := metricValue(metric, r)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if a == b || math.Abs(b-a) < threshold {
+			continue
+		}
+		e.Changes = append(e.Changes, &FeedChange{
+			Metric:   metric,
+			Previous: a,
+			Current:  b,
+		})
+	}
+	if len(e.Changes) == 0 {
+		return nil, nil
+	}
+	return e, nil
+}
+
+// renderFeed renders the Atom feed ( feed.xml ) of the significant changes of the metrics.
+// The feed is updated at the time of the newest report so that it is not changed until the reports are changed.
+func (c *Central) renderFeed(wr io.Writer, d *IndexData) error {
+	entries, err := c.feedEntries()
+	if err != nil {
+		return err
+	}
+	var updated time.Time
+	for _, r := range d.Reports {
+		if r.Timestamp.After(updated) {
+			updated = r.Timestamp
+		}
+	}
+	u := fmt.Sprintf("%s/%s", d.Host, d.Repository)
+	f := &atomFeed{
+		XMLNS:   atomNS,
+		ID:      u,
+		Title:   fmt.Sprintf("Code metrics changes of %s", d.Repository),
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links:   []*atomLink{{Rel: "alternate", Href: u}},
+		Author:  &atomAuthor{Name: "octocov"},
+		Entries: []*atomEntry{},
+	}
+	for _, e := range entries {
+		r := e.Current
+		repoURL := fmt.Sprintf("%s/%s", d.Host, r.Repository)
+		commitURL := fmt.Sprintf("%s/commit/%s", repoURL, r.Commit)
+		changes := []string{}
+		for _, ch := range e.Changes {
+			changes = append(changes, formatFeedChange(ch))
+		}
+		f.Entries = append(f.Entries, &atomEntry{
+			ID:      fmt.Sprintf("%s#%d", commitURL, r.Timestamp.Unix()),
+			Title:   fmt.Sprintf("%s: %s", r.Repository, strings.Join(changes, ", ")),
+			Updated: r.Timestamp.UTC().Format(time.RFC3339),
+			Links: []*atomLink{
+				{Rel: "alternate", Href: commitURL},
+				{Rel: "related", Href: repoURL},
+			},
+			Content: &atomContent{
+				Type: "html",
+				Body: feedDiffTable(e, repoURL),
+			},
+		})
+	}
+	if _, err := io.WriteString(wr, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(wr)
+	enc.Indent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	_, err = io.WriteString(wr, "\n")
+	return err
+}
+
+// feedDiffTable renders the HTML table of the metrics of the previous report and the newest report.
+func feedDiffTable(e *FeedEntry, repoURL string) string {
+	a, b := e.Previous, e.Current
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, `<p><a href="%s">%s...%s</a></p>`, html.EscapeString(fmt.Sprintf("%s/compare/%s...%s", repoURL, a.Commit, b.Commit)), shortCommit(a.Commit), shortCommit(b.Commit))
+	_, _ = fmt.Fprintf(&sb, "<table><tr><th></th><th>%s</th><th>%s</th><th>+/-</th></tr>", shortCommit(a.Commit), shortCommit(b.Commit))
+	for _, metric := range feedMetrics {
+		va, oka, _ := metricValue(metric, a)
+		vb, okb, _ := metricValue(metric, b)
+		if !oka || !okb {
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>", feedMetricNames[metric], formatMetric(metric, va), formatMetric(metric, vb), formatMetricDiff(metric, vb-va))
+	}
+	sb.WriteString("</table>")
+	return sb.String()
+}
+
+var feedMetricNames = map[string]string{
+	"coverage": "Coverage",
+	"ratio":    "Code to Test Ratio",
+	"time":     "Test Execution Time",
+}
+
+// formatFeedChange formats the change of the metric ( e.g. coverage 68.5% (+2.3%) ).
+func formatFeedChange(ch *FeedChange) string {
+	return fmt.Sprintf("%s %s (%s)", strings.ToLower(feedMetricNames[ch.Metric]), formatMetric(ch.Metric, ch.Current), formatMetricDiff(ch.Metric, ch.Current-ch.Previous))
+}
+
+func formatMetric(metric string, v float64) string {
+	switch metric {
+	case "coverage":
+		return fmt.Sprintf("%.1f%%", v)
+	case "ratio":
+		return fmt.Sprintf("1:%.1f", v)
+	case "time":
+		return time.Duration(v).String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func formatMetricDiff(metric string, diff float64) string {
+	switch metric {
+	case "coverage":
+		return fmt.Sprintf("%+.1f%%", diff)
+	case "ratio":
+		return fmt.Sprintf("%+.1f", diff)
+	case "time":
+		if diff < 0 {
+			return "-" + time.Duration(-diff).String()
+		}
+		return "+" + time.Duration(diff).String()
+	default:
+		return fmt.Sprintf("%+v", diff)
+	}
+}
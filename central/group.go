@@ -0,0 +1,122 @@
+package central
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/k1LoW/octocov/report"
+)
+
+// groupBadgesDir is the directory of the badges of the groups. It never collides with the directory of an owner because '@' is not allowed in the owner name.
+const groupBadgesDir = "@groups"
+
+// groupsDir is the directory of the pages of the groups in the HTML site.
+const groupsDir = "groups"
+
+var groupNameRe = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// Group is the definition of the group of repositories.
+type Group struct {
+	Name string
+	// Repositories is glob patterns of the repositories ( owner/repo ) in the group
+	Repositories []string
+	// Topic is the GitHub topic of the repositories in the group
+	Topic string
+}
+
+// GroupReports is the latest reports of the repositories in the group.
+type GroupReports struct {
+	Name string
+	// BadgesDir is the URL path of the directory of the badges of the group relative to the badges directory
+	BadgesDir string
+	// Reports is the latest reports of the repositories in the group, sorted by repository
+	Reports []*report.Report
+	// Summary is the aggregate metrics of the reports
+	Summary *Summary
+}
+
+// groupReports groups the visible reports by Groups. A repository may belong to more than one group.
+func (c *Central) groupReports(ctx context.Context) []*GroupReports {
+	thresholds := c.config.SummaryThresholds
+	if len(thresholds) == 0 {
+		thresholds = defaultSummaryThresholds
+	}
+	topics := map[string][]string{}
+	groups := []*GroupReports{}
+	for _, g := range c.config.Groups {
+		if !validGroupName(g.Name) {
+			_, _ = fmt.Fprintf(os.Stderr, "Skip group: invalid name (%s)\n", g.Name)
+			continue
+		}
+		rs := []*report.Report{}
+		for _, r := range c.visibleReports() {
+			if c.inGroup(ctx, g, r, topics) {
+				rs = append(rs, r)
+			}
+		}
+		groups = append(groups, &GroupReports{
+			Name:      g.Name,
+			BadgesDir: path.Join(groupBadgesDir, url.PathEscape(g.Name)),
+			Reports:   rs,
+			Summary:   summarize(rs, thresholds),
+		})
+	}
+	return groups
+}
+
+// validGroupName reports whether the name can be used as the directory name of the group.
+func validGroupName(name string) bool {
+	return groupNameRe.MatchString(name) && strings.Trim(name, ".") != "" && !strings.Contains(name, "..")
+}
+
+func (c *Central) inGroup(ctx context.Context, g *Group, r *report.Report, topics map[string][]string) bool {
+	for _, p := range g.Repositories {
+		if ok, _ := path.Match(p, r.Repository); ok {
+			return true
+		}
+	}
+	if g.Topic == "" || c.config.Topics == nil {
+		return false
+	}
+	ts, ok := topics[r.Repository]
+	if !ok {
+		var err error
+		ts, err = c.config.Topics(ctx, r.Repository)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not get the topics of %s: %v\n", r.Repository, err)
+		}
+		topics[r.Repository] = ts
+	}
+	return internal.Contains(ts, g.Topic)
+}
+
+// generateGroupBadges generates the badge of the line-weighted total code coverage of each group ( @groups/[name]/coverage.svg ).
+func (c *Central) generateGroupBadges(ctx context.Context) ([]string, error) {
+	badges := map[string][]byte{}
+	for _, g := range c.groups {
+		if g.Summary.Total == 0 {
+			continue
+		}
+		b := badge.New("coverage", fmt.Sprintf("%.1f%%", g.Summary.Coverage))
+		b.MessageColor = c.config.CoverageColor(g.Summary.Coverage)
+		b.Style = c.config.BadgeStyle
+		if err := b.AddIcon(internal.Icon); err != nil {
+			return nil, err
+		}
+		if err := c.renderBadge(badges, b, filepath.Join(groupBadgesDir, g.Name, "coverage")); err != nil {
+			return nil, err
+		}
+	}
+	for path, content := range badges {
+		c.badges[path] = content
+	}
+	return c.putBadges(ctx, badges)
+}
@@ -0,0 +1,113 @@
+package central
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/chart"
+)
+
+type historyEntry struct {
+	Timestamp         time.Time `json:"timestamp"`
+	Commit            string    `json:"commit"`
+	Ref               string    `json:"ref"`
+	Coverage          *float64  `json:"coverage,omitempty"`
+	CodeToTestRatio   *float64  `json:"code_to_test_ratio,omitempty"`
+	TestExecutionTime *float64  `json:"test_execution_time,omitempty"`
+}
+
+// generateHistory generates history.json and the line charts of the metrics of each repository from all collected reports.
+func (c *Central) generateHistory() (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, r := range c.reports {
+		rs := c.history[r.Repository]
+		entries := []historyEntry{}
+		coverage := []chart.Point{}
+		ratio := []chart.Point{}
+		times := []chart.Point{}
+		max := 0.0
+		for _, rr := range rs {
+			e := historyEntry{
+				Timestamp: rr.Timestamp,
+				Commit:    rr.Commit,
+				Ref:       rr.Ref,
+			}
+			label := shortCommit(rr.Commit)
+			if rr.IsMeasuredCoverage() {
+				v := rr.CoveragePercent()
+				e.Coverage = &v
+				coverage = append(coverage, chart.Point{Time: rr.Timestamp, Value: v, Label: label})
+			}
+			if rr.IsMeasuredCodeToTestRatio() {
+				v := rr.CodeToTestRatioRatio()
+				e.CodeToTestRatio = &v
+				ratio = append(ratio, chart.Point{Time: rr.Timestamp, Value: v, Label: label})
+			}
+			if rr.IsMeasuredTestExecutionTime() {
+				v := rr.TestExecutionTimeNano()
+				e.TestExecutionTime = &v
+				times = append(times, chart.Point{Time: rr.Timestamp, Value: v, Label: label})
+				if v > max {
+					max = v
+				}
+			}
+			entries = append(entries, e)
+		}
+		b, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(r.Repository, "history.json")] = b
+
+		if len(coverage) > 0 {
+			ch := chart.New("Coverage (%)", coverage)
+			min, max := 0.0, 100.0
+			ch.YMin, ch.YMax = &min, &max
+			ch.Format = func(v float64) string { return fmt.Sprintf("%.0f%%", v) }
+			if err := renderChart(files, ch, filepath.Join(r.Repository, "coverage-chart.svg")); err != nil {
+				return nil, err
+			}
+		}
+		if len(ratio) > 0 {
+			ch := chart.New("Code to Test Ratio", ratio)
+			ch.Format = func(v float64) string { return fmt.Sprintf("1:%.2f", v) }
+			if err := renderChart(files, ch, filepath.Join(r.Repository, "ratio-chart.svg")); err != nil {
+				return nil, err
+			}
+		}
+		if len(times) > 0 {
+			unit, title := time.Second, "Test Execution Time (s)"
+			if max >= float64(2*time.Minute) {
+				unit, title = time.Minute, "Test Execution Time (min)"
+			}
+			for i := range times {
+				times[i].Value = times[i].Value / float64(unit)
+			}
+			ch := chart.New(title, times)
+			ch.Format = func(v float64) string { return fmt.Sprintf("%.1f", v) }
+			if err := renderChart(files, ch, filepath.Join(r.Repository, "time-chart.svg")); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+func renderChart(files map[string][]byte, ch *chart.LineChart, path string) error {
+	out := new(bytes.Buffer)
+	if err := ch.Render(out); err != nil {
+		return err
+	}
+	files[path] = out.Bytes()
+	return nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
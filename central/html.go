@@ -0,0 +1,297 @@
+package central
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/k1LoW/octocov/report"
+)
+
+//go:embed layout.html.tmpl
+var layoutHTMLTmpl []byte
+
+//go:embed index.html.tmpl
+var indexHTMLTmpl []byte
+
+//go:embed repo.html.tmpl
+var repoHTMLTmpl []byte
+
+type htmlBadge struct {
+	Alt string
+	Src string
+}
+
+type htmlRepository struct {
+	Repository    string
+	URL           string
+	Page          string
+	Root          string
+	Coverage      string
+	CoverageValue float64
+	Ratio         string
+	RatioValue    float64
+	Time          string
+	TimeValue     float64
+	Ref           string
+	Commit        string
+	CommitURL     string
+	Updated       string
+	Stale         bool
+	Badges        []htmlBadge
+	Charts        []htmlBadge
+	Files         []htmlFile
+}
+
+type htmlGroup struct {
+	Name          string
+	Page          string
+	Repositories  int
+	Coverage      string
+	CoverageValue float64
+	Badge         *htmlBadge
+}
+
+type htmlFile struct {
+	File          string
+	Coverage      string
+	CoverageValue float64
+	Covered       int
+	Total         int
+}
+
+// generateHTML generates the static HTML site ( index page, page of each repository and badges ) in the HTML directory, and returns the paths of the changed files.
+func (c *Central) generateHTML() ([]string, error) {
+	host := os.Getenv("GITHUB_SERVER_URL")
+	if host == "" {
+		host = gh.DefaultGithubServerURL
+	}
+	root := c.config.HTML
+	paths := []string{}
+
+	// copy badges so that the site is self-contained
+	ext := c.htmlBadgeExt()
+	for p, content := range c.badges {
+		if (ext == "" || filepath.Ext(p) != ext) && !strings.HasSuffix(p, "-chart.svg") {
+			continue
+		}
+		bp := filepath.Join(root, "badges", p)
+		changed, err := writeFileIfChanged(bp, content)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			paths = append(paths, bp)
+		}
+	}
+
+	repos := []*htmlRepository{}
+	for _, r := range c.visibleReports() {
+		repos = append(repos, c.htmlRepository(host, ext, r))
+	}
+
+	groups := []*htmlGroup{}
+	for _, g := range c.groups {
+		groups = append(groups, c.htmlGroup(ext, g))
+	}
+
+	index := template.Must(template.Must(template.New("layout").Parse(string(layoutHTMLTmpl))).New("index").Parse(string(indexHTMLTmpl)))
+	summary := c.summarize()
+	ip := filepath.Join(root, "index.html")
+	changed, err := renderHTMLIndex(index, ip, map[string]interface{}{
+		"Title":        "",
+		"Root":         "",
+		"Repositories": repos,
+		"Summary":      summary,
+		"TotalTime":    time.Duration(summary.TestExecutionTime).String(),
+		"Groups":       groups,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		paths = append(paths, ip)
+	}
+
+	// page of each group
+	for i, g := range c.groups {
+		grepos := []*htmlRepository{}
+		for _, r := range g.Reports {
+			grepos = append(grepos, c.htmlRepository(host, ext, r))
+		}
+		gp := filepath.Join(root, groupsDir, g.Name, "index.html")
+		changed, err := renderHTMLIndex(index, gp, map[string]interface{}{
+			"Title":        g.Name,
+			"Root":         strings.Repeat("../", strings.Count(groups[i].Page, "/")),
+			"Badge":        groups[i].Badge,
+			"Repositories": grepos,
+			"Summary":      g.Summary,
+			"TotalTime":    time.Duration(g.Summary.TestExecutionTime).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			paths = append(paths, gp)
+		}
+	}
+
+	page := template.Must(template.Must(template.New("layout").Parse(string(layoutHTMLTmpl))).New("repo").Parse(string(repoHTMLTmpl)))
+	for _, repo := range repos {
+		buf := new(bytes.Buffer)
+		if err := page.ExecuteTemplate(buf, "repo", repo); err != nil {
+			return nil, err
+		}
+		rp := filepath.Join(root, filepath.FromSlash(repo.Page))
+		changed, err := writeFileIfChanged(rp, buf.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			paths = append(paths, rp)
+		}
+	}
+
+	return paths, nil
+}
+
+// htmlBadgeExt returns the extension of the badge images embedded in the HTML site ( empty if no image badges are generated ).
+func (c *Central) htmlBadgeExt() string {
+	if len(c.config.BadgeFormats) == 0 || internal.Contains(c.config.BadgeFormats, badge.FormatSVG) {
+		return ".svg"
+	}
+	if internal.Contains(c.config.BadgeFormats, badge.FormatPNG) {
+		return ".png"
+	}
+	return ""
+}
+
+func (c *Central) htmlRepository(host, ext string, r *report.Report) *htmlRepository {
+	page := path.Join("repos", r.Repository, "index.html")
+	// relative path from the page of the repository to the root of the site
+	root := strings.Repeat("../", strings.Count(page, "/"))
+	repo := &htmlRepository{
+		Repository:    r.Repository,
+		URL:           fmt.Sprintf("%s/%s", host, r.Repository),
+		Page:          page,
+		Root:          root,
+		Coverage:      "-",
+		CoverageValue: -1,
+		Ratio:         "-",
+		RatioValue:    -1,
+		Time:          "-",
+		TimeValue:     -1,
+		Ref:           r.Ref,
+		Commit:        r.Commit,
+		Updated:       r.Timestamp.UTC().Format(time.RFC3339),
+		Stale:         c.isStale(r),
+		Badges:        []htmlBadge{},
+		Charts:        []htmlBadge{},
+		Files:         []htmlFile{},
+	}
+	if r.Commit != "" {
+		repo.CommitURL = fmt.Sprintf("%s/%s/commit/%s", host, r.Repository, r.Commit)
+	}
+	if r.IsMeasuredCoverage() {
+		repo.Coverage = fmt.Sprintf("%.1f%%", r.CoveragePercent())
+		repo.CoverageValue = r.CoveragePercent()
+		for _, f := range r.Coverage.Files {
+			cover := 0.0
+			if f.Total > 0 {
+				cover = float64(f.Covered) / float64(f.Total) * 100
+			}
+			repo.Files = append(repo.Files, htmlFile{
+				File:          f.File,
+				Coverage:      fmt.Sprintf("%.1f%%", cover),
+				CoverageValue: cover,
+				Covered:       f.Covered,
+				Total:         f.Total,
+			})
+		}
+		sort.Slice(repo.Files, func(i, j int) bool { return repo.Files[i].File < repo.Files[j].File })
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		repo.Ratio = fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio())
+		repo.RatioValue = r.CodeToTestRatioRatio()
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		repo.Time = time.Duration(r.TestExecutionTimeNano()).String()
+		repo.TimeValue = r.TestExecutionTimeNano()
+	}
+	if ext != "" {
+		for _, b := range []struct {
+			alt  string
+			name string
+		}{
+			{"Coverage", "coverage"},
+			{"Code to Test Ratio", "ratio"},
+			{"Test Execution Time", "time"},
+		} {
+			p := path.Join(r.Repository, b.name+ext)
+			if _, ok := c.badges[filepath.FromSlash(p)]; !ok {
+				continue
+			}
+			repo.Badges = append(repo.Badges, htmlBadge{Alt: b.alt, Src: path.Join("badges", p)})
+		}
+	}
+	for _, ch := range []struct {
+		alt  string
+		name string
+	}{
+		{"Coverage", "coverage-chart.svg"},
+		{"Code to Test Ratio", "ratio-chart.svg"},
+		{"Test Execution Time", "time-chart.svg"},
+	} {
+		p := path.Join(r.Repository, ch.name)
+		if _, ok := c.badges[filepath.FromSlash(p)]; !ok {
+			continue
+		}
+		repo.Charts = append(repo.Charts, htmlBadge{Alt: ch.alt, Src: path.Join("badges", p)})
+	}
+	return repo
+}
+
+func (c *Central) htmlGroup(ext string, g *GroupReports) *htmlGroup {
+	hg := &htmlGroup{
+		Name:          g.Name,
+		Page:          path.Join(groupsDir, url.PathEscape(g.Name), "index.html"),
+		Repositories:  g.Summary.Repositories,
+		Coverage:      "-",
+		CoverageValue: -1,
+	}
+	if g.Summary.Total > 0 {
+		hg.Coverage = fmt.Sprintf("%.1f%%", g.Summary.Coverage)
+		hg.CoverageValue = g.Summary.Coverage
+	}
+	p := path.Join(groupBadgesDir, g.Name, "coverage"+ext)
+	if _, ok := c.badges[filepath.FromSlash(p)]; ext != "" && ok {
+		hg.Badge = &htmlBadge{Alt: "Coverage", Src: path.Join("badges", g.BadgesDir, "coverage"+ext)}
+	}
+	return hg
+}
+
+func renderHTMLIndex(tmpl *template.Template, p string, data map[string]interface{}) (bool, error) {
+	buf := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(buf, "index", data); err != nil {
+		return false, err
+	}
+	return writeFileIfChanged(p, buf.Bytes())
+}
+
+func writeFile(p string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil { // #nosec
+		return err
+	}
+	return os.WriteFile(p, b, 0644) // #nosec
+}
@@ -0,0 +1,124 @@
+package central
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/badge"
+)
+
+// IndexJSONSchemaVersion is the version of the schema of index.json. It is incremented on incompatible changes.
+const IndexJSONSchemaVersion = 1
+
+// IndexJSON is the machine-readable index of the central repository ( index.json ).
+type IndexJSON struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Repository    string                 `json:"repository"`
+	Summary       *IndexJSONSummary      `json:"summary"`
+	Repositories  []*IndexJSONRepository `json:"repositories"`
+}
+
+type IndexJSONSummary struct {
+	Repositories      int      `json:"repositories"`
+	Coverage          *float64 `json:"coverage"`
+	Covered           int      `json:"covered"`
+	Total             int      `json:"total"`
+	CoverageMedian    *float64 `json:"coverage_median"`
+	TestExecutionTime float64  `json:"test_execution_time"`
+}
+
+type IndexJSONRepository struct {
+	Repository        string                    `json:"repository"`
+	URL               string                    `json:"url"`
+	Ref               string                    `json:"ref"`
+	Commit            string                    `json:"commit"`
+	Timestamp         time.Time                 `json:"timestamp"`
+	Stale             bool                      `json:"stale"`
+	Coverage          *IndexJSONCoverage        `json:"coverage"`
+	CodeToTestRatio   *IndexJSONCodeToTestRatio `json:"code_to_test_ratio"`
+	TestExecutionTime *float64                  `json:"test_execution_time"`
+	Badges            map[string]string         `json:"badges"`
+}
+
+type IndexJSONCoverage struct {
+	Percent float64 `json:"percent"`
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+}
+
+type IndexJSONCodeToTestRatio struct {
+	Ratio float64 `json:"ratio"`
+	Code  int     `json:"code"`
+	Test  int     `json:"test"`
+}
+
+// renderIndexJSON renders index.json of the latest reports.
+// Time-dependent values ( e.g. the time generated ) are not included so that it is not changed until the reports are changed.
+func (c *Central) renderIndexJSON(wr io.Writer, d *IndexData) error {
+	idx := &IndexJSON{
+		SchemaVersion: IndexJSONSchemaVersion,
+		Repository:    d.Repository,
+		Summary: &IndexJSONSummary{
+			Repositories:      d.Summary.Repositories,
+			Covered:           d.Summary.Covered,
+			Total:             d.Summary.Total,
+			TestExecutionTime: d.Summary.TestExecutionTime,
+		},
+		Repositories: []*IndexJSONRepository{},
+	}
+	if d.Summary.Total > 0 {
+		idx.Summary.Coverage = &d.Summary.Coverage
+		idx.Summary.CoverageMedian = &d.Summary.CoverageMedian
+	}
+	ext := c.htmlBadgeExt()
+	if ext == "" {
+		ext = "." + badge.FormatJSON
+	}
+	for _, r := range d.Reports {
+		repo := &IndexJSONRepository{
+			Repository: r.Repository,
+			URL:        fmt.Sprintf("%s/%s", d.Host, r.Repository),
+			Ref:        r.Ref,
+			Commit:     r.Commit,
+			Timestamp:  r.Timestamp,
+			Stale:      c.isStale(r),
+			Badges:     map[string]string{},
+		}
+		if r.IsMeasuredCoverage() {
+			repo.Coverage = &IndexJSONCoverage{
+				Percent: r.CoveragePercent(),
+				Covered: r.Coverage.Covered,
+				Total:   r.Coverage.Total,
+			}
+		}
+		if r.IsMeasuredCodeToTestRatio() {
+			repo.CodeToTestRatio = &IndexJSONCodeToTestRatio{
+				Ratio: r.CodeToTestRatioRatio(),
+				Code:  r.CodeToTestRatio.Code,
+				Test:  r.CodeToTestRatio.Test,
+			}
+		}
+		if r.IsMeasuredTestExecutionTime() {
+			v := r.TestExecutionTimeNano()
+			repo.TestExecutionTime = &v
+		}
+		for _, name := range []string{"coverage", "ratio", "time"} {
+			p := path.Join(r.Repository, name+ext)
+			if _, ok := c.badges[filepath.FromSlash(p)]; !ok {
+				continue
+			}
+			repo.Badges[name] = fmt.Sprintf("%s/%s/%s", d.RawRootURL, filepath.ToSlash(d.BadgesURLRel), p)
+		}
+		idx.Repositories = append(idx.Repositories, repo)
+	}
+	b, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = wr.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,51 @@
+package central
+
+import (
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+// https://github.com/badges/shields/blob/7d452472defa0e0bd71d6443393e522e8457f856/badge-maker/lib/color.js#L3-L19
+const staleColor = "#9F9F9F"
+
+// isStale returns true if the report is older than StaleAfter.
+func (c *Central) isStale(r *report.Report) bool {
+	if c.config.StaleAfter <= 0 {
+		return false
+	}
+	return time.Since(r.Timestamp) > c.config.StaleAfter
+}
+
+// StaleRepositories returns the repositories whose newest report is older than StaleAfter.
+func (c *Central) StaleRepositories() []string {
+	repos := []string{}
+	for _, r := range c.reports {
+		if c.isStale(r) {
+			repos = append(repos, r.Repository)
+		}
+	}
+	return repos
+}
+
+// visibleReports returns the reports shown in the index ( without stale reports if HideStale ).
+func (c *Central) visibleReports() []*report.Report {
+	if !c.config.HideStale {
+		return c.reports
+	}
+	reports := []*report.Report{}
+	for _, r := range c.reports {
+		if !c.isStale(r) {
+			reports = append(reports, r)
+		}
+	}
+	return reports
+}
+
+// badgeColor returns the grey color for the badges of the stale report.
+func (c *Central) badgeColor(r *report.Report, cl string) string {
+	if c.isStale(r) {
+		return staleColor
+	}
+	return cl
+}
@@ -0,0 +1,115 @@
+package central
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/k1LoW/octocov/report"
+)
+
+var defaultSummaryThresholds = []float64{50, 80}
+
+// Summary is the aggregate metrics of the collected reports.
+type Summary struct {
+	// Repositories is the number of repositories
+	Repositories int
+	// Coverage is the line-weighted total code coverage (%) of the repositories with coverage measured
+	Coverage float64
+	// Covered is the total number of covered lines ( or statements )
+	Covered int
+	// Total is the total number of lines ( or statements )
+	Total int
+	// CoverageMedian is the median of the code coverage (%) of the repositories with coverage measured
+	CoverageMedian float64
+	// Below is the number of the repositories whose code coverage is below each threshold
+	Below []*SummaryBelow
+	// TestExecutionTime is the total test execution time (ns)
+	TestExecutionTime float64
+}
+
+type SummaryBelow struct {
+	Threshold    float64
+	Repositories int
+}
+
+func (c *Central) summarize() *Summary {
+	thresholds := c.config.SummaryThresholds
+	if len(thresholds) == 0 {
+		thresholds = defaultSummaryThresholds
+	}
+	return summarize(c.visibleReports(), thresholds)
+}
+
+func summarize(reports []*report.Report, thresholds []float64) *Summary {
+	s := &Summary{
+		Repositories: len(reports),
+		Below:        []*SummaryBelow{},
+	}
+	for _, th := range thresholds {
+		s.Below = append(s.Below, &SummaryBelow{Threshold: th})
+	}
+	covs := []float64{}
+	for _, r := range reports {
+		if r.IsMeasuredCoverage() {
+			s.Covered += r.Coverage.Covered
+			s.Total += r.Coverage.Total
+			cp := r.CoveragePercent()
+			covs = append(covs, cp)
+			for _, b := range s.Below {
+				if cp < b.Threshold {
+					b.Repositories += 1
+				}
+			}
+		}
+		if r.IsMeasuredTestExecutionTime() {
+			s.TestExecutionTime += r.TestExecutionTimeNano()
+		}
+	}
+	if s.Total > 0 {
+		s.Coverage = float64(s.Covered) / float64(s.Total) * 100
+	}
+	if len(covs) > 0 {
+		sort.Float64s(covs)
+		m := len(covs) / 2
+		if len(covs)%2 == 0 {
+			s.CoverageMedian = (covs[m-1] + covs[m]) / 2
+		} else {
+			s.CoverageMedian = covs[m]
+		}
+	}
+	return s
+}
+
+// generateSummaryBadges generates the badge of the line-weighted total code coverage of each owner ( [owner]/coverage.svg ).
+func (c *Central) generateSummaryBadges(ctx context.Context) ([]string, error) {
+	owners := map[string][]*report.Report{}
+	for _, r := range c.visibleReports() {
+		owner := strings.Split(r.Repository, "/")[0]
+		owners[owner] = append(owners[owner], r)
+	}
+	badges := map[string][]byte{}
+	for owner, rs := range owners {
+		s := summarize(rs, nil)
+		if s.Total == 0 {
+			continue
+		}
+		b := badge.New("coverage", fmt.Sprintf("%.1f%%", s.Coverage))
+		b.MessageColor = c.config.CoverageColor(s.Coverage)
+		b.Style = c.config.BadgeStyle
+		if err := b.AddIcon(internal.Icon); err != nil {
+			return nil, err
+		}
+		if err := c.renderBadge(badges, b, filepath.Join(owner, "coverage")); err != nil {
+			return nil, err
+		}
+	}
+	for path, content := range badges {
+		c.badges[path] = content
+	}
+	return c.putBadges(ctx, badges)
+}
@@ -0,0 +1,180 @@
+package central
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const defaultTemplateTrendLimit = 10
+
+// IndexData is the data passed to the index template ( embedded index.md.tmpl or central.template ).
+type IndexData struct {
+	// Host is the URL of the GitHub server ( e.g. https://github.com )
+	Host string
+	// Repository is the central repository ( owner/repo )
+	Repository string
+	// Reports is the latest reports of the repositories, sorted by repository
+	Reports []*report.Report
+	// History is all reports collected for the ref of the latest report of each repository, sorted by timestamp
+	History map[string][]*report.Report
+	// Branches is the latest report of each branch of each repository
+	Branches map[string]map[string]*report.Report
+	// Summary is the aggregate metrics of the latest reports
+	Summary *Summary
+	// Groups is the latest reports and the aggregate metrics of each group, in the order of central.groups
+	Groups []*GroupReports
+	// BadgesLinkRel is the relative path from the index to the badges directory
+	BadgesLinkRel string
+	// BadgesURLRel is the relative path from the root of the central repository to the badges directory
+	BadgesURLRel string
+	// RawRootURL is the URL of the raw content of the central repository
+	RawRootURL string
+	// Timestamp is the time the index is generated
+	Timestamp time.Time
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+func (c *Central) funcs() template.FuncMap {
+	return template.FuncMap{
+		"coverage": func(r *report.Report) string {
+			return fmt.Sprintf("%.1f%%", r.CoveragePercent())
+		},
+		"ratio": func(r *report.Report) string {
+			if r.CodeToTestRatio == nil {
+				return "-"
+			}
+			return fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio())
+		},
+		"time": func(r *report.Report) string {
+			if r.TestExecutionTime == nil {
+				return "-"
+			}
+			return time.Duration(r.TestExecutionTimeNano()).String()
+		},
+		"percent": func(v float64) string {
+			return fmt.Sprintf("%.1f%%", v)
+		},
+		"duration": func(ns float64) string {
+			return time.Duration(ns).String()
+		},
+		"ago": func(t time.Time) string {
+			return relativeTime(t, time.Now())
+		},
+		"stale": c.isStale,
+		"trend": c.trend,
+		"color": c.color,
+	}
+}
+
+// trend returns the unicode sparkline of the metric ( coverage, ratio or time ) of all reports collected for the repository.
+func (c *Central) trend(metric string, r *report.Report) (string, error) {
+	limit := c.config.BadgeTrendLimit
+	if limit == 0 {
+		limit = defaultTemplateTrendLimit
+	}
+	values := []float64{}
+	for _, rr := range c.history[r.Repository] {
+		v, ok, err := metricValue(metric, rr)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return "-", nil
+	}
+	if len(values) > limit {
+		values = values[len(values)-limit:]
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	var sb strings.Builder
+	for _, v := range values {
+		i := len(sparks) / 2
+		if max > min {
+			i = int(math.Round((v - min) / (max - min) * float64(len(sparks)-1)))
+		}
+		sb.WriteRune(sparks[i])
+	}
+	return sb.String(), nil
+}
+
+// color returns the badge color of the metric ( coverage, ratio or time ) of the report.
+func (c *Central) color(metric string, r *report.Report) (string, error) {
+	v, ok, err := metricValue(metric, r)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", nil
+	}
+	switch metric {
+	case "coverage":
+		return c.config.CoverageColor(v), nil
+	case "ratio":
+		return c.config.CodeToTestRatioColor(v), nil
+	default:
+		return c.config.TestExecutionTimeColor(time.Duration(v)), nil
+	}
+}
+
+func metricValue(metric string, r *report.Report) (float64, bool, error) {
+	switch metric {
+	case "coverage":
+		if !r.IsMeasuredCoverage() {
+			return 0, false, nil
+		}
+		return r.CoveragePercent(), true, nil
+	case "ratio":
+		if !r.IsMeasuredCodeToTestRatio() {
+			return 0, false, nil
+		}
+		return r.CodeToTestRatioRatio(), true, nil
+	case "time":
+		if !r.IsMeasuredTestExecutionTime() {
+			return 0, false, nil
+		}
+		return r.TestExecutionTimeNano(), true, nil
+	default:
+		return 0, false, fmt.Errorf("invalid metric: %s", metric)
+	}
+}
+
+// relativeTime returns the time relative to now in words ( e.g. 3 days ago ).
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		d = 0
+	}
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month")
+	default:
+		return plural(int(d/(365*24*time.Hour)), "year")
+	}
+}
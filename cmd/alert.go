@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+)
+
+// alertReport opens or updates the alert issue when the report has regressions, and closes it when the report has recovered.
+func alertReport(ctx context.Context, c *config.Config, r, rPrev *report.Report) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	key := ""
+	if c.Comment != nil {
+		key = c.Comment.Key
+	}
+	regressions := detectRegressions(c, r, rPrev)
+	if len(regressions) > 0 && c.Alerts.Issue.Consecutive > 1 {
+		n, err := consecutiveRegressions(ctx, c, r, rPrev)
+		if err != nil {
+			return err
+		}
+		if n < c.Alerts.Issue.Consecutive {
+			_, _ = fmt.Fprintf(os.Stderr, "Regressions are found in the last %d of %d consecutive reports required to open the alert issue\n", n, c.Alerts.Issue.Consecutive)
+			return nil
+		}
+	}
+	if len(regressions) == 0 {
+		comment := fmt.Sprintf("Code metrics are acceptable again as of %s.", r.Commit)
+		closed, err := g.CloseAlertIssues(ctx, repo.Owner, repo.Repo, comment, c.Alerts.Issue.Labels, key)
+		if err != nil {
+			return err
+		}
+		for _, n := range closed {
+			_, _ = fmt.Fprintf(os.Stderr, "Closed the alert issue #%d\n", n)
+		}
+		return nil
+	}
+	body := buildAlertIssueBody(r, rPrev, regressions, c.Alerts.Issue.Consecutive)
+	n, err := g.PutAlertIssue(ctx, repo.Owner, repo.Repo, c.Alerts.Issue.Title, body, c.Alerts.Issue.Labels, key)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Reported regressions to the alert issue #%d\n", n)
+	return nil
+}
+
+// consecutiveRegressions returns the number of the consecutive reports with regressions up to the current report ( at most alerts.issue.consecutive ).
+// The previous reports are the reports of the default branch kept as history in diff.datastores.
+func consecutiveRegressions(ctx context.Context, c *config.Config, r, rPrev *report.Report) (int, error) {
+	n := c.Alerts.Issue.Consecutive
+	// one more report to compare the oldest report with
+	history, err := loadTrendReports(ctx, c, n+1)
+	if err != nil {
+		return 0, err
+	}
+	reports := []*report.Report{}
+	for _, h := range history {
+		// the report of the same commit stored by the previous run
+		if h.Commit == r.Commit {
+			continue
+		}
+		reports = append(reports, h)
+	}
+	if len(reports) == 0 && rPrev != nil {
+		reports = append(reports, rPrev)
+	}
+	reports = append(reports, r)
+	count := 0
+	for i := len(reports) - 1; i >= 0 && count < n; i-- {
+		var prev *report.Report
+		if i > 0 {
+			prev = reports[i-1]
+		}
+		if len(detectRegressions(c, reports[i], prev)) == 0 {
+			break
+		}
+		count++
+	}
+	return count, nil
+}
+
+func detectRegressions(c *config.Config, r, rPrev *report.Report) []string {
+	regressions := []string{}
+	if err := c.Acceptable(r, rPrev); err != nil {
+		if merr, ok := err.(*multierror.Error); ok {
+			for _, err := range merr.Errors {
+				regressions = append(regressions, capitalize(err.Error()))
+			}
+		} else {
+			regressions = append(regressions, capitalize(err.Error()))
+		}
+	}
+	drop := c.Alerts.Issue.MaxCoverageDrop
+	if drop > 0 && rPrev != nil && r.IsMeasuredCoverage() && rPrev.IsMeasuredCoverage() {
+		diff := r.CoveragePercent() - rPrev.CoveragePercent()
+		if diff < -drop {
+			regressions = append(regressions, fmt.Sprintf("Code coverage dropped by %.1f%% (the maximum drop is %.1f%%)", -diff, drop))
+		}
+	}
+	return regressions
+}
+
+func buildAlertIssueBody(r, rPrev *report.Report, regressions []string, consecutive int) string {
+	var table string
+	if rPrev != nil {
+		table = rPrev.Compare(r).Table()
+	} else {
+		table = r.Table()
+	}
+	body := []string{fmt.Sprintf("## Code Metrics Regression (%s)", r.Commit), ""}
+	if consecutive > 1 {
+		body = append(body, fmt.Sprintf("Regressions are found in the last %d consecutive reports on the default branch.", consecutive), "")
+	}
+	for _, reg := range regressions {
+		body = append(body, fmt.Sprintf("- :no_entry_sign: %s", reg))
+	}
+	body = append(
+		body,
+		"",
+		table,
+		"",
+		"This issue will be closed automatically when the code metrics are acceptable again.",
+		"",
+		"---",
+		"Reported by [octocov](https://github.com/k1LoW/octocov)",
+	)
+	return strings.Join(body, "\n")
+}
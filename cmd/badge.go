@@ -42,7 +42,14 @@ const (
 	badgeTime     = "time"
 )
 
-var outPath string
+var (
+	outPath     string
+	badgeStyle  string
+	badgeFormat string
+	badgeScale  int
+	badgeTrend  bool
+	trendLimit  int
+)
 
 // badgeCmd represents the badge command
 var badgeCmd = &cobra.Command{
@@ -58,12 +65,28 @@ var badgeCmd = &cobra.Command{
 			return err
 		}
 		c.Build()
+		if err := c.BadgeColorsReady(); err != nil {
+			return err
+		}
+		if badgeTrend && badgeFormat == badge.FormatJSON {
+			return fmt.Errorf("--trend can not be used with --format %s", badge.FormatJSON)
+		}
 
 		r, err := report.New(c.Repository)
 		if err != nil {
 			return err
 		}
 
+		style := c.BadgeStyle()
+		if badgeStyle != "" {
+			if !internal.Contains(badge.Styles, badgeStyle) {
+				return fmt.Errorf("invalid style: %s ( %s )", badgeStyle, strings.Join(badge.Styles, ", "))
+			}
+			style = badgeStyle
+		} else if err := c.BadgeStyleReady(); err != nil {
+			return err
+		}
+
 		var out io.Writer
 		if outPath != "" {
 			file, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
@@ -91,10 +114,18 @@ var badgeCmd = &cobra.Command{
 			cp := r.CoveragePercent()
 			b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
 			b.MessageColor = c.CoverageColor(cp)
+			if badgeTrend {
+				if err := addTrend(ctx, c, b, cp, func(rr *report.Report) (float64, bool) {
+					return rr.CoveragePercent(), rr.IsMeasuredCoverage()
+				}); err != nil {
+					return err
+				}
+			}
+			b.Style = style
 			if err := b.AddIcon(internal.Icon); err != nil {
 				return err
 			}
-			if err := b.Render(out); err != nil {
+			if err := renderBadge(b, out); err != nil {
 				return err
 			}
 		case badgeRatio:
@@ -110,10 +141,18 @@ var badgeCmd = &cobra.Command{
 			tr := r.CodeToTestRatioRatio()
 			b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
 			b.MessageColor = c.CodeToTestRatioColor(tr)
+			if badgeTrend {
+				if err := addTrend(ctx, c, b, tr, func(rr *report.Report) (float64, bool) {
+					return rr.CodeToTestRatioRatio(), rr.IsMeasuredCodeToTestRatio()
+				}); err != nil {
+					return err
+				}
+			}
+			b.Style = style
 			if err := b.AddIcon(internal.Icon); err != nil {
 				return err
 			}
-			if err := b.Render(out); err != nil {
+			if err := renderBadge(b, out); err != nil {
 				return err
 			}
 		case badgeTime:
@@ -130,10 +169,18 @@ var badgeCmd = &cobra.Command{
 			d := time.Duration(r.TestExecutionTimeNano())
 			b := badge.New("test execution time", d.String())
 			b.MessageColor = c.TestExecutionTimeColor(d)
+			if badgeTrend {
+				if err := addTrend(ctx, c, b, r.TestExecutionTimeNano(), func(rr *report.Report) (float64, bool) {
+					return rr.TestExecutionTimeNano(), rr.IsMeasuredTestExecutionTime()
+				}); err != nil {
+					return err
+				}
+			}
+			b.Style = style
 			if err := b.AddIcon(internal.Icon); err != nil {
 				return err
 			}
-			if err := b.Render(out); err != nil {
+			if err := renderBadge(b, out); err != nil {
 				return err
 			}
 		}
@@ -142,8 +189,45 @@ var badgeCmd = &cobra.Command{
 	},
 }
 
+// addTrend adds the sparkline of the values of the previous reports of the default branch and the current value to the badge.
+func addTrend(ctx context.Context, c *config.Config, b *badge.Badge, current float64, value func(r *report.Report) (float64, bool)) error {
+	if trendLimit < 2 {
+		return fmt.Errorf("invalid trend limit: %d", trendLimit)
+	}
+	reports, err := loadTrendReports(ctx, c, trendLimit-1)
+	if err != nil {
+		return err
+	}
+	values := []float64{}
+	for _, r := range reports {
+		if v, ok := value(r); ok {
+			values = append(values, v)
+		}
+	}
+	b.Trend = append(values, current)
+	return nil
+}
+
+func renderBadge(b *badge.Badge, out io.Writer) error {
+	switch badgeFormat {
+	case badge.FormatSVG, "":
+		return b.Render(out)
+	case badge.FormatPNG:
+		return b.RenderPNG(out, badgeScale)
+	case badge.FormatJSON:
+		return b.RenderJSON(out)
+	default:
+		return fmt.Errorf("invalid badge format: %s", badgeFormat)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(badgeCmd)
 	badgeCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
 	badgeCmd.Flags().StringVarP(&outPath, "out", "", "", "output file path")
+	badgeCmd.Flags().StringVarP(&badgeStyle, "style", "", "", fmt.Sprintf("badge style (%s)", strings.Join(badge.Styles, ", ")))
+	badgeCmd.Flags().StringVarP(&badgeFormat, "format", "", badge.FormatSVG, fmt.Sprintf("badge format (%s)", strings.Join(badge.Formats, ", ")))
+	badgeCmd.Flags().IntVarP(&badgeScale, "scale", "", 1, fmt.Sprintf("scale of PNG badge (1 for 1x, 2 for 2x, up to %d)", badge.MaxPNGScale))
+	badgeCmd.Flags().BoolVarP(&badgeTrend, "trend", "", false, "add sparkline of the values of the previous reports stored in diff.datastores")
+	badgeCmd.Flags().IntVarP(&trendLimit, "trend-limit", "", 10, "number of values in the sparkline")
 }
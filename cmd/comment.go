@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
@@ -28,6 +29,61 @@ func commentReport(ctx context.Context, c *config.Config, r, rPrev *report.Repor
 	if err != nil {
 		return err
 	}
+	var trend []*report.Report
+	if c.Comment.Trend {
+		trend, err = loadTrendReports(ctx, c, c.Comment.TrendLimit)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Skip rendering the trend chart: %v\n", err)
+			trend = nil
+		}
+	}
+	comment := buildComment(c, r, rPrev, files, trend)
+	if err := g.PutComment(ctx, repo.Owner, repo.Repo, n, comment, &gh.CommentOptions{
+		Mode:  c.Comment.Mode,
+		Key:   c.Comment.Key,
+		Merge: c.Comment.Merge,
+	}); err != nil {
+		return err
+	}
+
+	if c.Comment.Inline {
+		comments := []*gh.ReviewComment{}
+		for _, lr := range r.UncoveredLineRanges(files) {
+			body := fmt.Sprintf(":warning: Line %d is not covered by tests.", lr.StartLine)
+			if lr.StartLine < lr.EndLine {
+				body = fmt.Sprintf(":warning: Lines %d-%d are not covered by tests.", lr.StartLine, lr.EndLine)
+			}
+			comments = append(comments, &gh.ReviewComment{
+				Path:      lr.File,
+				StartLine: lr.StartLine,
+				Line:      lr.EndLine,
+				Body:      body,
+			})
+		}
+		if err := g.PutReviewComments(ctx, repo.Owner, repo.Repo, n, comments, c.Comment.Key, c.Comment.InlineLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commentReportToCommit(ctx context.Context, c *config.Config, r, rPrev *report.Report) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	comment := buildComment(c, r, rPrev, nil, nil)
+	if err := g.PutCommitComment(ctx, repo.Owner, repo.Repo, r.Commit, comment, c.Comment.Key); err != nil {
+		return err
+	}
+	return nil
+}
+
+func buildComment(c *config.Config, r, rPrev *report.Report, files []*gh.PullRequestFile, trend []*report.Report) string {
 	footer := "Reported by [octocov](https://github.com/k1LoW/octocov)"
 	if c.Comment.HideFooterLink {
 		footer = "Reported by octocov"
@@ -42,7 +98,11 @@ func commentReport(ctx context.Context, c *config.Config, r, rPrev *report.Repor
 		fileTable = r.FileCoveagesTable(files)
 	}
 
-	comment := []string{"## Code Metrics Report"}
+	title := "## Code Metrics Report"
+	if c.Comment.Key != "" {
+		title = fmt.Sprintf("%s (%s)", title, c.Comment.Key)
+	}
+	comment := []string{title}
 
 	if err := c.Acceptable(r, rPrev); err != nil {
 		merr := err.(*multierror.Error)
@@ -61,14 +121,29 @@ func commentReport(ctx context.Context, c *config.Config, r, rPrev *report.Repor
 		table,
 		"",
 		fileTable,
+	)
+
+	if trend != nil {
+		comment = append(
+			comment,
+			"<details>",
+			"",
+			"<summary>Trend</summary>",
+			"",
+			report.TrendChart(trend, r),
+			"",
+			"</details>",
+			"",
+		)
+	}
+
+	comment = append(
+		comment,
 		"---",
 		footer,
 	)
 
-	if err := g.PutComment(ctx, repo.Owner, repo.Repo, n, strings.Join(comment, "\n")); err != nil {
-		return err
-	}
-	return nil
+	return strings.Join(comment, "\n")
 }
 
 func capitalize(w string) string {
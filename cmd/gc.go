@@ -0,0 +1,90 @@
+/*
+Copyright © 2022 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/spf13/cobra"
+)
+
+var dryRun bool
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "delete the reports kept as history according to report.retention",
+	Long:  `delete the reports kept as history in report.datastores according to report.retention.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		c := config.New()
+		if err := c.Load(configPath); err != nil {
+			return err
+		}
+		if !c.Loaded() {
+			cmd.PrintErrf("%s are not found\n", strings.Join(config.DefaultConfigFilePaths, " and "))
+		}
+
+		c.Build()
+
+		if err := c.ReportRetentionConfigReady(); err != nil {
+			return err
+		}
+		within, err := c.ReportRetentionWithin()
+		if err != nil {
+			return err
+		}
+		rt := &datastore.Retention{
+			Last:   c.Report.Retention.Last,
+			Within: within,
+			Weekly: c.Report.Retention.Weekly,
+		}
+		for _, u := range c.Report.Datastores {
+			d, err := datastore.New(ctx, u, c.Root())
+			if err != nil {
+				return err
+			}
+			expired, err := datastore.Prune(ctx, d, c.Repository, rt, dryRun)
+			if err != nil {
+				return err
+			}
+			for _, p := range expired {
+				cmd.Println(p)
+			}
+			if dryRun {
+				cmd.PrintErrf("%d reports would be deleted from %s\n", len(expired), u)
+			} else {
+				cmd.PrintErrf("%d reports have been deleted from %s\n", len(expired), u)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	gcCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "list the reports to be deleted without deleting them")
+}
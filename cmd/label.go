@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+)
+
+func labelPullRequest(ctx context.Context, c *config.Config, r, rPrev *report.Report) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	n, err := g.DetectCurrentPullRequestNumber(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return err
+	}
+	files, err := g.GetPullRequestFiles(ctx, repo.Owner, repo.Repo, n)
+	if err != nil {
+		return err
+	}
+	add, remove, err := c.MatchLabels(r, rPrev, files)
+	if err != nil {
+		return err
+	}
+	return g.UpdateLabels(ctx, repo.Owner, repo.Repo, n, add, remove)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/notify"
+	"github.com/k1LoW/octocov/report"
+)
+
+func notifyReport(ctx context.Context, c *config.Config, r, rPrev *report.Report) error {
+	var d *report.DiffReport
+	if rPrev != nil {
+		d = rPrev.Compare(r)
+	}
+	var result *multierror.Error
+	for i, w := range c.Notify.Webhooks {
+		ok, err := c.CheckIf(w.If)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("notify.webhooks[%d]: %w", i, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := notify.NewWebhook(w.URL, w.Headers, w.Body).Send(ctx, r, d); err != nil {
+			result = multierror.Append(result, fmt.Errorf("notify.webhooks[%d]: %w", i, err))
+		}
+	}
+	return result.ErrorOrNil()
+}
@@ -31,6 +31,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/k1LoW/octocov/central"
@@ -106,6 +107,52 @@ var rootCmd = &cobra.Command{
 				reports = append(reports, d)
 			}
 
+			thresholds, err := c.CentralSummaryThresholds()
+			if err != nil {
+				return err
+			}
+
+			staleAfter, err := c.CentralStaleAfter()
+			if err != nil {
+				return err
+			}
+
+			defaultBranch := defaultBranchResolver()
+			branchBadges := false
+			tracked := []string{}
+			if c.Central.Branches != nil {
+				branchBadges = c.Central.Branches.Badges
+				tracked = c.Central.Branches.Tracked
+			}
+
+			groups := []*central.Group{}
+			for _, g := range c.Central.Groups {
+				groups = append(groups, &central.Group{
+					Name:         g.Name,
+					Repositories: g.Repositories,
+					Topic:        g.Topic,
+				})
+			}
+
+			html := ""
+			if err := c.CentralHTMLConfigReady(); err != nil {
+				cmd.PrintErrf("Skip generating HTML site: %v\n", err)
+			} else {
+				html = c.Central.HTML.Root
+			}
+
+			feed := false
+			var feedThresholds map[string]float64
+			if err := c.CentralFeedConfigReady(); err != nil {
+				cmd.PrintErrf("Skip generating feed: %v\n", err)
+			} else {
+				feed = true
+				feedThresholds, err = c.CentralFeedThresholds()
+				if err != nil {
+					return err
+				}
+			}
+
 			ctr := central.New(&central.CentralConfig{
 				Repository:             c.Repository,
 				Index:                  c.Central.Root,
@@ -115,20 +162,48 @@ var rootCmd = &cobra.Command{
 				CoverageColor:          c.CoverageColor,
 				CodeToTestRatioColor:   c.CodeToTestRatioColor,
 				TestExecutionTimeColor: c.TestExecutionTimeColor,
+				BadgeStyle:             c.BadgeStyle(),
+				BadgeFormats:           c.Central.Badges.Formats,
+				BadgeTrend:             c.Central.Badges.Trend,
+				BadgeTrendLimit:        c.Central.Badges.TrendLimit,
+				HTML:                   html,
+				Template:               c.Central.Template,
+				History:                c.Central.History,
+				SummaryThresholds:      thresholds,
+				StaleAfter:             staleAfter,
+				HideStale:              c.Central.HideStale,
+				DefaultBranch:          defaultBranch,
+				BranchBadges:           branchBadges,
+				TrackedBranches:        tracked,
+				Concurrency:            c.Central.Reports.Concurrency,
+				Groups:                 groups,
+				Topics:                 topicsResolver(),
+				Feed:                   feed,
+				FeedThresholds:         feedThresholds,
 			})
 			paths, err := ctr.Generate(ctx)
 			if err != nil {
 				return err
 			}
+			changes := ctr.Changes()
+			cmd.PrintErrf("Repositories: %d updated, %d unchanged, %d removed\n", len(changes.Updated), len(changes.Unchanged), len(changes.Removed))
 			// git push
 			if err := c.CentralPushConfigReady(); err != nil {
 				cmd.PrintErrf("Skip commit and push central report: %v\n", err)
+			} else if len(paths) == 0 {
+				cmd.PrintErrln("Skip commit and push central report: nothing changed")
 			} else {
 				cmd.PrintErrln("Commit and push central report")
 				if err := gh.PushUsingLocalGit(ctx, c.GitRoot, paths, "Update by octocov"); err != nil {
 					return err
 				}
 			}
+			if stale := ctr.StaleRepositories(); len(stale) > 0 {
+				cmd.PrintErrf("Stale reports found: %s\n", strings.Join(stale, ", "))
+				if c.Central.FailOnStale {
+					return fmt.Errorf("stale reports found (%d repositories)", len(stale))
+				}
+			}
 			return nil
 		}
 
@@ -175,6 +250,14 @@ var rootCmd = &cobra.Command{
 		}
 		cmd.Println("")
 
+		// Invalid badge colors or style should not be regarded as the badges not being configured
+		if err := c.BadgeColorsReady(); err != nil {
+			return err
+		}
+		if err := c.BadgeStyleReady(); err != nil {
+			return err
+		}
+
 		// Generate coverage report badge
 		if err := c.CoverageBadgeConfigReady(); err == nil {
 			if err := func() error {
@@ -205,6 +288,7 @@ var rootCmd = &cobra.Command{
 
 				b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
 				b.MessageColor = c.CoverageColor(cp)
+				b.Style = c.BadgeStyle()
 				if err := b.AddIcon(internal.Icon); err != nil {
 					return err
 				}
@@ -248,6 +332,7 @@ var rootCmd = &cobra.Command{
 
 				b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
 				b.MessageColor = c.CodeToTestRatioColor(tr)
+				b.Style = c.BadgeStyle()
 				if err := b.AddIcon(internal.Icon); err != nil {
 					return err
 				}
@@ -291,6 +376,7 @@ var rootCmd = &cobra.Command{
 				d := time.Duration(r.TestExecutionTimeNano())
 				b := badge.New("test execution time", d.String())
 				b.MessageColor = c.TestExecutionTimeColor(d)
+				b.Style = c.BadgeStyle()
 				if err := b.AddIcon(internal.Icon); err != nil {
 					return err
 				}
@@ -356,8 +442,10 @@ var rootCmd = &cobra.Command{
 		} else {
 			if err := func() error {
 				cmd.PrintErrln("Commenting report...")
-				if err := c.DiffConfigReady(); rPrev == nil || err != nil {
+				if err := c.DiffConfigReady(); err != nil {
 					cmd.PrintErrf("Skip comparing reports: %v\n", err)
+				} else if rPrev == nil {
+					cmd.PrintErrln("Skip comparing reports: previous report is not found")
 				}
 				if err := commentReport(ctx, c, r, rPrev); err != nil {
 					return err
@@ -368,6 +456,41 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// Label pull request
+		if err := c.LabelsConfigReady(); err != nil {
+			cmd.PrintErrf("Skip labeling pull request: %v\n", err)
+		} else {
+			cmd.PrintErrln("Labeling pull request...")
+			if err := labelPullRequest(ctx, c, r, rPrev); err != nil {
+				cmd.PrintErrf("Skip labeling pull request: %v\n", err)
+			}
+		}
+
+		// Comment report to commit
+		if err := c.CommitCommentConfigReady(); err != nil {
+			cmd.PrintErrf("Skip commenting report to commit: %v\n", err)
+		} else {
+			cmd.PrintErrln("Commenting report to commit...")
+			if err := c.DiffConfigReady(); err != nil {
+				cmd.PrintErrf("Skip comparing reports: %v\n", err)
+			} else if rPrev == nil {
+				cmd.PrintErrln("Skip comparing reports: previous report is not found")
+			}
+			if err := commentReportToCommit(ctx, c, r, rPrev); err != nil {
+				cmd.PrintErrf("Skip commenting the report to commit: %v\n", err)
+			}
+		}
+
+		// Alert regressions
+		if err := c.AlertsIssueConfigReady(); err != nil {
+			cmd.PrintErrf("Skip alerting regressions: %v\n", err)
+		} else {
+			cmd.PrintErrln("Checking regressions...")
+			if err := alertReport(ctx, c, r, rPrev); err != nil {
+				cmd.PrintErrf("Skip alerting regressions: %v\n", err)
+			}
+		}
+
 		// Store report
 		if err := c.ReportConfigReady(); err != nil {
 			cmd.PrintErrf("Skip storing the report: %v\n", err)
@@ -401,6 +524,21 @@ var rootCmd = &cobra.Command{
 				if err := d.StoreReport(ctx, r); err != nil {
 					return err
 				}
+				if c.Report.History {
+					if err := datastore.StoreReportHistory(ctx, d, r); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// Notify report
+		if err := c.NotifyConfigReady(); err != nil {
+			cmd.PrintErrf("Skip notifying the report: %v\n", err)
+		} else {
+			cmd.PrintErrln("Notifying report...")
+			if err := notifyReport(ctx, c, r, rPrev); err != nil {
+				cmd.PrintErrf("Failed to notify the report: %v\n", err)
 			}
 		}
 
@@ -464,6 +602,57 @@ func printMetrics(cmd *cobra.Command) error {
 	return nil
 }
 
+// defaultBranchResolver returns the function to get the default branch of the repository using GitHub API.
+// The function is safe for concurrent use.
+func defaultBranchResolver() func(ctx context.Context, repository string) (string, error) {
+	var (
+		once sync.Once
+		g    *gh.Gh
+		gerr error
+	)
+	return func(ctx context.Context, repository string) (string, error) {
+		repo, err := gh.Parse(repository)
+		if err != nil {
+			return "", err
+		}
+		once.Do(func() {
+			g, gerr = gh.New()
+		})
+		if gerr != nil {
+			return "", gerr
+		}
+		return g.GetDefaultBranch(ctx, repo.Owner, repo.Repo)
+	}
+}
+
+// topicsResolver returns the function to get the topics of the repository using GitHub API.
+func topicsResolver() func(ctx context.Context, repository string) ([]string, error) {
+	var g *gh.Gh
+	cache := map[string][]string{}
+	return func(ctx context.Context, repository string) ([]string, error) {
+		repo, err := gh.Parse(repository)
+		if err != nil {
+			return nil, err
+		}
+		k := fmt.Sprintf("%s/%s", repo.Owner, repo.Repo)
+		if topics, ok := cache[k]; ok {
+			return topics, nil
+		}
+		if g == nil {
+			g, err = gh.New()
+			if err != nil {
+				return nil, err
+			}
+		}
+		topics, err := g.ListTopics(ctx, repo.Owner, repo.Repo)
+		if err != nil {
+			return nil, err
+		}
+		cache[k] = topics
+		return topics, nil
+	}
+}
+
 func init() {
 	rootCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
 	rootCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
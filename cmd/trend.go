@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+)
+
+// loadTrendReports returns the last n reports of the default branch stored in the diff datastores, sorted by timestamp.
+func loadTrendReports(ctx context.Context, c *config.Config, n int) ([]*report.Report, error) {
+	if c.Diff == nil || len(c.Diff.Datastores) == 0 {
+		return nil, errors.New("diff.datastores: is not set")
+	}
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return nil, err
+	}
+	defaultBranch, err := g.GetDefaultBranch(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return nil, err
+	}
+	dir := fmt.Sprintf("%s/%s", repo.Owner, repo.Reponame())
+	seen := map[string]struct{}{}
+	reports := []*report.Report{}
+	for _, s := range c.Diff.Datastores {
+		d, err := datastore.New(ctx, s, c.Root())
+		if err != nil {
+			return nil, err
+		}
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, err
+		}
+		rs, err := datastore.ReadReports(fsys, dir)
+		if err != nil {
+			continue
+		}
+		for _, r := range rs {
+			if strings.TrimPrefix(r.Ref, "refs/heads/") != defaultBranch {
+				continue
+			}
+			// report.json is also kept as history
+			k := fmt.Sprintf("%s-%d", r.Commit, r.Timestamp.UnixNano())
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			reports = append(reports, r)
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Timestamp.Before(reports[j].Timestamp)
+	})
+	if len(reports) > n {
+		reports = reports[len(reports)-n:]
+	}
+	return reports, nil
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/duration"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
+)
+
+type ConfigBadge struct {
+	Style  string             `yaml:"style,omitempty"`
+	Colors *ConfigBadgeColors `yaml:"colors,omitempty"`
+}
+
+// ConfigBadgeColors is the colors of the badges of each metric.
+// The colors in the badge config of each metric ( e.g. coverage.badge.colors ) override them.
+type ConfigBadgeColors struct {
+	Coverage          []*ConfigBadgeColor `yaml:"coverage,omitempty"`
+	CodeToTestRatio   []*ConfigBadgeColor `yaml:"codeToTestRatio,omitempty"`
+	TestExecutionTime []*ConfigBadgeColor `yaml:"testExecutionTime,omitempty"`
+}
+
+type ConfigBadgeColor struct {
+	Threshold string `yaml:"threshold,omitempty"`
+	Color     string `yaml:"color"`
+}
+
+// https://github.com/badges/shields/blob/7d452472defa0e0bd71d6443393e522e8457f856/badge-maker/lib/color.js#L3-L19
+var namedColors = map[string]string{
+	"brightgreen":   "#4C1",
+	"green":         green,
+	"yellow":        yellow,
+	"yellowgreen":   yellowgreen,
+	"orange":        orange,
+	"red":           red,
+	"blue":          "#007EC6",
+	"grey":          "#555",
+	"gray":          "#555",
+	"lightgrey":     "#9F9F9F",
+	"lightgray":     "#9F9F9F",
+	"success":       "#4C1",
+	"important":     orange,
+	"critical":      red,
+	"informational": "#007EC6",
+	"inactive":      "#9F9F9F",
+}
+
+// BadgeStyle returns the style of badges ( empty if not set ).
+func (c *Config) BadgeStyle() string {
+	if c.Badge == nil {
+		return ""
+	}
+	return c.Badge.Style
+}
+
+// BadgeStyleReady validates badge.style.
+// It is checked separately from *BadgeConfigReady so that an invalid style is not silently regarded as the badge not being configured.
+func (c *Config) BadgeStyleReady() error {
+	if c.Badge == nil || c.Badge.Style == "" {
+		return nil
+	}
+	if !internal.Contains(badge.Styles, c.Badge.Style) {
+		return fmt.Errorf("badge.style: invalid style (%s)", c.Badge.Style)
+	}
+	return nil
+}
+
+var hexColorRe = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// normalizeColor returns the hex color of the named shields color or the hex value.
+func normalizeColor(cl string) (string, error) {
+	cl = strings.TrimSpace(cl)
+	if v, ok := namedColors[strings.ToLower(cl)]; ok {
+		return v, nil
+	}
+	m := hexColorRe.FindStringSubmatch(cl)
+	if m == nil {
+		return "", fmt.Errorf("invalid color: %s", cl)
+	}
+	return fmt.Sprintf("#%s", strings.ToUpper(m[1])), nil
+}
+
+type badgeColorRule struct {
+	threshold float64
+	color     string
+	fallback  bool
+}
+
+func parseBadgeColors(colors []*ConfigBadgeColor, parseThreshold func(string) (float64, error)) ([]badgeColorRule, error) {
+	rules := []badgeColorRule{}
+	for i, c := range colors {
+		cl, err := normalizeColor(c.Color)
+		if err != nil {
+			return nil, fmt.Errorf("[%d].color: %w", i, err)
+		}
+		if c.Threshold == "" {
+			rules = append(rules, badgeColorRule{color: cl, fallback: true})
+			continue
+		}
+		th, err := parseThreshold(c.Threshold)
+		if err != nil {
+			return nil, fmt.Errorf("[%d].threshold: invalid threshold (%s)", i, c.Threshold)
+		}
+		rules = append(rules, badgeColorRule{threshold: th, color: cl})
+	}
+	return rules, nil
+}
+
+// matchBadgeColor returns the color of the highest threshold that v reaches ( or the lowest threshold that v is less than, if lower is better ).
+func matchBadgeColor(colors []*ConfigBadgeColor, parseThreshold func(string) (float64, error), v float64, lowerIsBetter bool) (string, error) {
+	rules, err := parseBadgeColors(colors, parseThreshold)
+	if err != nil {
+		return "", err
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if lowerIsBetter {
+			return rules[i].threshold < rules[j].threshold
+		}
+		return rules[i].threshold > rules[j].threshold
+	})
+	fallback := red
+	for _, r := range rules {
+		if r.fallback {
+			fallback = r.color
+			continue
+		}
+		if (lowerIsBetter && v < r.threshold) || (!lowerIsBetter && v >= r.threshold) {
+			return r.color, nil
+		}
+	}
+	return fallback, nil
+}
+
+// coverageBadgeColors returns coverage.badge.colors, or badge.colors.coverage if not set.
+func (c *Config) coverageBadgeColors() []*ConfigBadgeColor {
+	if c.Coverage != nil && len(c.Coverage.Badge.Colors) > 0 {
+		return c.Coverage.Badge.Colors
+	}
+	if c.Badge != nil && c.Badge.Colors != nil {
+		return c.Badge.Colors.Coverage
+	}
+	return nil
+}
+
+// codeToTestRatioBadgeColors returns codeToTestRatio.badge.colors, or badge.colors.codeToTestRatio if not set.
+func (c *Config) codeToTestRatioBadgeColors() []*ConfigBadgeColor {
+	if c.CodeToTestRatio != nil && len(c.CodeToTestRatio.Badge.Colors) > 0 {
+		return c.CodeToTestRatio.Badge.Colors
+	}
+	if c.Badge != nil && c.Badge.Colors != nil {
+		return c.Badge.Colors.CodeToTestRatio
+	}
+	return nil
+}
+
+// testExecutionTimeBadgeColors returns testExecutionTime.badge.colors, or badge.colors.testExecutionTime if not set.
+func (c *Config) testExecutionTimeBadgeColors() []*ConfigBadgeColor {
+	if c.TestExecutionTime != nil && len(c.TestExecutionTime.Badge.Colors) > 0 {
+		return c.TestExecutionTime.Badge.Colors
+	}
+	if c.Badge != nil && c.Badge.Colors != nil {
+		return c.Badge.Colors.TestExecutionTime
+	}
+	return nil
+}
+
+// BadgeColorsReady validates the badge colors of all metrics.
+// It is checked separately from *BadgeConfigReady so that invalid colors are not silently regarded as the badge not being configured.
+func (c *Config) BadgeColorsReady() error {
+	if c.Badge != nil && c.Badge.Colors != nil {
+		if err := validateBadgeColors("badge.colors.coverage", c.Badge.Colors.Coverage, parseCoverageThreshold); err != nil {
+			return err
+		}
+		if err := validateBadgeColors("badge.colors.codeToTestRatio", c.Badge.Colors.CodeToTestRatio, parseCodeToTestRatioThreshold); err != nil {
+			return err
+		}
+		if err := validateBadgeColors("badge.colors.testExecutionTime", c.Badge.Colors.TestExecutionTime, parseTestExecutionTimeThreshold); err != nil {
+			return err
+		}
+	}
+	if c.Coverage != nil {
+		if err := validateBadgeColors("coverage.badge.colors", c.Coverage.Badge.Colors, parseCoverageThreshold); err != nil {
+			return err
+		}
+	}
+	if c.CodeToTestRatio != nil {
+		if err := validateBadgeColors("codeToTestRatio.badge.colors", c.CodeToTestRatio.Badge.Colors, parseCodeToTestRatioThreshold); err != nil {
+			return err
+		}
+	}
+	if c.TestExecutionTime != nil {
+		if err := validateBadgeColors("testExecutionTime.badge.colors", c.TestExecutionTime.Badge.Colors, parseTestExecutionTimeThreshold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateBadgeColors(key string, colors []*ConfigBadgeColor, parseThreshold func(string) (float64, error)) error {
+	if _, err := parseBadgeColors(colors, parseThreshold); err != nil {
+		return fmt.Errorf("%s%w", key, err)
+	}
+	return nil
+}
+
+func parseCoverageThreshold(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+}
+
+func parseCodeToTestRatioThreshold(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(s), "1:"), 64)
+}
+
+func parseTestExecutionTimeThreshold(s string) (float64, error) {
+	d, err := duration.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("threshold must be positive")
+	}
+	return float64(d), nil
+}
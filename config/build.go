@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/internal"
 )
 
@@ -61,15 +62,53 @@ func (c *Config) Build() {
 		if len(c.Central.Badges.Datastores) == 0 {
 			c.Central.Badges.Datastores = append(c.Central.Badges.Datastores, defaultBadgesDatastore)
 		}
+		if c.Central.Template != "" && !strings.HasPrefix(c.Central.Template, "/") {
+			c.Central.Template = filepath.Clean(filepath.Join(c.Root(), c.Central.Template))
+		}
+		if c.Central.HTML != nil {
+			if c.Central.HTML.Root == "" {
+				c.Central.HTML.Root = defaultCentralHTMLRoot
+			}
+			if !strings.HasPrefix(c.Central.HTML.Root, "/") {
+				c.Central.HTML.Root = filepath.Clean(filepath.Join(c.Root(), c.Central.HTML.Root))
+			}
+		}
+		if c.Central.Badges.Trend && c.Central.Badges.TrendLimit == 0 {
+			c.Central.Badges.TrendLimit = defaultTrendLimit
+		}
 	}
 
 	// Push
 
-	// Comment
-
 	// Diff
 
+	// Alerts
+	if c.Alerts != nil && c.Alerts.Issue != nil {
+		if c.Alerts.Issue.Title == "" {
+			c.Alerts.Issue.Title = defaultAlertsIssueTitle
+		}
+		if len(c.Alerts.Issue.Labels) == 0 {
+			c.Alerts.Issue.Labels = defaultAlertsIssueLabels
+		}
+		if c.Alerts.Issue.Consecutive == 0 {
+			c.Alerts.Issue.Consecutive = defaultAlertsIssueConsecutive
+		}
+	}
+
 	// GitRoot
 	gitRoot, _ := internal.GetRootPath(c.Root())
 	c.GitRoot = gitRoot
+
+	// Comment
+	if c.Comment != nil {
+		if c.Comment.Mode == "" {
+			c.Comment.Mode = gh.CommentModeRecreate
+		}
+		if c.Comment.Inline && c.Comment.InlineLimit == 0 {
+			c.Comment.InlineLimit = defaultInlineCommentLimit
+		}
+		if c.Comment.Trend && c.Comment.TrendLimit == 0 {
+			c.Comment.TrendLimit = defaultTrendLimit
+		}
+	}
 }
@@ -22,6 +22,14 @@ import (
 const defaultBadgesDatastore = "local://reports"
 const defaultReportsDatastore = "local://reports"
 const largeEnoughTime = float64(99 * time.Hour)
+const defaultInlineCommentLimit = 20
+const defaultTrendLimit = 10
+const defaultCentralHTMLRoot = "docs"
+const defaultCentralFeedCoverageThreshold = 2.0
+const defaultAlertsIssueTitle = "Code metrics regression detected by octocov"
+const defaultAlertsIssueConsecutive = 3
+
+var defaultAlertsIssueLabels = []string{"octocov-alert"}
 
 const (
 	// https://github.com/badges/shields/blob/7d452472defa0e0bd71d6443393e522e8457f856/badge-maker/lib/color.js#L8-L12
@@ -44,6 +52,10 @@ type Config struct {
 	Push              *ConfigPush              `yaml:"push,omitempty"`
 	Comment           *ConfigComment           `yaml:"comment,omitempty"`
 	Diff              *ConfigDiff              `yaml:"diff,omitempty"`
+	Notify            *ConfigNotify            `yaml:"notify,omitempty"`
+	Alerts            *ConfigAlerts            `yaml:"alerts,omitempty"`
+	Labels            *ConfigLabels            `yaml:"labels,omitempty"`
+	Badge             *ConfigBadge             `yaml:"badge,omitempty"`
 	GitRoot           string                   `yaml:"-"`
 	// working directory
 	wd string
@@ -60,7 +72,8 @@ type ConfigCoverage struct {
 }
 
 type ConfigCoverageBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path   string              `yaml:"path,omitempty"`
+	Colors []*ConfigBadgeColor `yaml:"colors,omitempty"`
 }
 
 type ConfigCodeToTestRatio struct {
@@ -71,7 +84,8 @@ type ConfigCodeToTestRatio struct {
 }
 
 type ConfigCodeToTestRatioBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path   string              `yaml:"path,omitempty"`
+	Colors []*ConfigBadgeColor `yaml:"colors,omitempty"`
 }
 
 type ConfigTestExecutionTime struct {
@@ -81,24 +95,70 @@ type ConfigTestExecutionTime struct {
 }
 
 type ConfigTestExecutionTimeBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path   string              `yaml:"path,omitempty"`
+	Colors []*ConfigBadgeColor `yaml:"colors,omitempty"`
 }
 
 type ConfigCentral struct {
-	Enable  *bool                `yaml:"enable,omitempty"`
-	Root    string               `yaml:"root"`
-	Reports ConfigCentralReports `yaml:"reports"`
-	Badges  ConfigCentralBadges  `yaml:"badges"`
-	Push    *ConfigPush          `yaml:"push"`
-	If      string               `yaml:"if,omitempty"`
+	Enable      *bool                  `yaml:"enable,omitempty"`
+	Root        string                 `yaml:"root"`
+	Reports     ConfigCentralReports   `yaml:"reports"`
+	Badges      ConfigCentralBadges    `yaml:"badges"`
+	HTML        *ConfigCentralHTML     `yaml:"html,omitempty"`
+	Template    string                 `yaml:"template,omitempty"`
+	History     bool                   `yaml:"history,omitempty"`
+	Summary     *ConfigCentralSummary  `yaml:"summary,omitempty"`
+	StaleAfter  string                 `yaml:"staleAfter,omitempty"`
+	HideStale   bool                   `yaml:"hideStale,omitempty"`
+	FailOnStale bool                   `yaml:"failOnStale,omitempty"`
+	Branches    *ConfigCentralBranches `yaml:"branches,omitempty"`
+	Groups      []*ConfigCentralGroup  `yaml:"groups,omitempty"`
+	Feed        *ConfigCentralFeed     `yaml:"feed,omitempty"`
+	Push        *ConfigPush            `yaml:"push"`
+	If          string                 `yaml:"if,omitempty"`
 }
 
 type ConfigCentralReports struct {
-	Datastores []string `yaml:"datastores"`
+	Datastores  []string `yaml:"datastores"`
+	Concurrency int      `yaml:"concurrency,omitempty"`
 }
 
 type ConfigCentralBadges struct {
 	Datastores []string `yaml:"datastores"`
+	Formats    []string `yaml:"formats,omitempty"`
+	Trend      bool     `yaml:"trend,omitempty"`
+	TrendLimit int      `yaml:"trendLimit,omitempty"`
+}
+
+type ConfigCentralSummary struct {
+	Thresholds []string `yaml:"thresholds,omitempty"`
+}
+
+type ConfigCentralGroup struct {
+	Name         string   `yaml:"name"`
+	Repositories []string `yaml:"repositories,omitempty"`
+	Topic        string   `yaml:"topic,omitempty"`
+}
+
+type ConfigCentralBranches struct {
+	Badges  bool     `yaml:"badges,omitempty"`
+	Tracked []string `yaml:"tracked,omitempty"`
+}
+
+type ConfigCentralHTML struct {
+	Enable *bool  `yaml:"enable,omitempty"`
+	Root   string `yaml:"root,omitempty"`
+}
+
+type ConfigCentralFeed struct {
+	Enable     *bool                        `yaml:"enable,omitempty"`
+	Thresholds *ConfigCentralFeedThresholds `yaml:"thresholds,omitempty"`
+}
+
+type ConfigCentralFeedThresholds struct {
+	Coverage          string `yaml:"coverage,omitempty"`
+	CodeToTestRatio   string `yaml:"codeToTestRatio,omitempty"`
+	TestExecutionTime string `yaml:"testExecutionTime,omitempty"`
 }
 
 type ConfigPush struct {
@@ -109,6 +169,14 @@ type ConfigPush struct {
 type ConfigComment struct {
 	Enable         *bool  `yaml:"enable,omitempty"`
 	HideFooterLink bool   `yaml:"hideFooterLink"`
+	Mode           string `yaml:"mode,omitempty"`
+	Key            string `yaml:"key,omitempty"`
+	Merge          bool   `yaml:"merge,omitempty"`
+	Inline         bool   `yaml:"inline,omitempty"`
+	InlineLimit    int    `yaml:"inlineLimit,omitempty"`
+	Commit         bool   `yaml:"commit,omitempty"`
+	Trend          bool   `yaml:"trend,omitempty"`
+	TrendLimit     int    `yaml:"trendLimit,omitempty"`
 	If             string `yaml:"if,omitempty"`
 }
 
@@ -118,6 +186,41 @@ type ConfigDiff struct {
 	If         string   `yaml:"if,omitempty"`
 }
 
+type ConfigNotify struct {
+	Webhooks []*ConfigNotifyWebhook `yaml:"webhooks,omitempty"`
+}
+
+type ConfigNotifyWebhook struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+	If      string            `yaml:"if,omitempty"`
+}
+
+type ConfigAlerts struct {
+	Issue *ConfigAlertsIssue `yaml:"issue,omitempty"`
+}
+
+type ConfigAlertsIssue struct {
+	Enable          *bool    `yaml:"enable,omitempty"`
+	Title           string   `yaml:"title,omitempty"`
+	Labels          []string `yaml:"labels,omitempty"`
+	MaxCoverageDrop float64  `yaml:"maxCoverageDrop,omitempty"`
+	Consecutive     int      `yaml:"consecutive,omitempty"`
+	If              string   `yaml:"if,omitempty"`
+}
+
+type ConfigLabels struct {
+	Enable *bool              `yaml:"enable,omitempty"`
+	Rules  []*ConfigLabelRule `yaml:"rules,omitempty"`
+	If     string             `yaml:"if,omitempty"`
+}
+
+type ConfigLabelRule struct {
+	Name      string `yaml:"name"`
+	Condition string `yaml:"condition"`
+}
+
 func New() *Config {
 	wd, _ := os.Getwd()
 	return &Config{
@@ -155,6 +258,32 @@ func (c *Config) Load(path string) error {
 	if err := yaml.Unmarshal(expand.ExpandenvYAMLBytes(buf), c); err != nil {
 		return err
 	}
+	if err := c.loadWebhookBodies(buf); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadWebhookBodies sets the bodies of notify.webhooks without expanding environment variables,
+// because `$` of the template variables ( e.g. `{{ $r := .Report }}` ) would be expanded to empty strings.
+func (c *Config) loadWebhookBodies(buf []byte) error {
+	if c.Notify == nil {
+		return nil
+	}
+	raw := struct {
+		Notify *ConfigNotify `yaml:"notify,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(buf, &raw); err != nil {
+		return err
+	}
+	if raw.Notify == nil {
+		return nil
+	}
+	for i, w := range raw.Notify.Webhooks {
+		if i < len(c.Notify.Webhooks) {
+			c.Notify.Webhooks[i].Body = w.Body
+		}
+	}
 	return nil
 }
 
@@ -312,7 +441,98 @@ func testExecutionTimeAcceptable(current, prev float64, cond string) error {
 	return nil
 }
 
+// MatchLabels evaluates the conditions of labels.rules against the reports and returns the labels to be added and to be removed.
+// The rules with the same label name are merged, and the label is added if any of the conditions is met.
+func (c *Config) MatchLabels(r, rPrev *report.Report, files []*gh.PullRequestFile) ([]string, []string, error) {
+	variables := labelVariables(r, rPrev, files)
+	names := []string{}
+	matched := map[string]bool{}
+	for _, rule := range c.Labels.Rules {
+		if _, ok := matched[rule.Name]; !ok {
+			names = append(names, rule.Name)
+			matched[rule.Name] = false
+		}
+		// Trim '%'
+		cond := trimPercentRe.ReplaceAllString(rule.Condition, "$1")
+		ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid condition of the label %s (`%s`): %w", rule.Name, rule.Condition, err)
+		}
+		if ok.(bool) {
+			matched[rule.Name] = true
+		}
+	}
+	add := []string{}
+	remove := []string{}
+	for _, name := range names {
+		if matched[name] {
+			add = append(add, name)
+		} else {
+			remove = append(remove, name)
+		}
+	}
+	return add, remove, nil
+}
+
+// labelVariables returns the variables of the conditions of labels.rules.
+// The differences are 0 if the metric of the previous report is not measured.
+func labelVariables(r, rPrev *report.Report, files []*gh.PullRequestFile) map[string]interface{} {
+	coverage := r.CoveragePercent()
+	ratio := r.CodeToTestRatioRatio()
+	executionTime := 0.0
+	if r.IsMeasuredTestExecutionTime() {
+		executionTime = time.Duration(r.TestExecutionTimeNano()).Seconds()
+	}
+	coveragePrev := 0.0
+	ratioPrev := 0.0
+	executionTimePrev := 0.0
+	coverageDiff := 0.0
+	ratioDiff := 0.0
+	executionTimeDiff := 0.0
+	if rPrev != nil {
+		coveragePrev = rPrev.CoveragePercent()
+		ratioPrev = rPrev.CodeToTestRatioRatio()
+		if rPrev.IsMeasuredCoverage() {
+			coverageDiff = coverage - coveragePrev
+		}
+		if r.IsMeasuredCodeToTestRatio() && rPrev.IsMeasuredCodeToTestRatio() {
+			ratioDiff = ratio - ratioPrev
+		}
+		if rPrev.IsMeasuredTestExecutionTime() {
+			executionTimePrev = time.Duration(rPrev.TestExecutionTimeNano()).Seconds()
+			if r.IsMeasuredTestExecutionTime() {
+				executionTimeDiff = executionTime - executionTimePrev
+			}
+		}
+	}
+	// When the pull request adds no coverable lines, there is nothing to test.
+	patchCoverage := 100.0
+	if covered, total := r.PatchCoverage(files); total > 0 {
+		patchCoverage = float64(covered) / float64(total) * 100
+	}
+	return map[string]interface{}{
+		"current":                  coverage,
+		"prev":                     coveragePrev,
+		"diff":                     coverageDiff,
+		"coverage":                 coverage,
+		"coverage_prev":            coveragePrev,
+		"coverage_diff":            coverageDiff,
+		"patch_coverage":           patchCoverage,
+		"code_to_test_ratio":       ratio,
+		"code_to_test_ratio_prev":  ratioPrev,
+		"code_to_test_ratio_diff":  ratioDiff,
+		"test_execution_time":      executionTime,
+		"test_execution_time_prev": executionTimePrev,
+		"test_execution_time_diff": executionTimeDiff,
+	}
+}
+
 func (c *Config) CoverageColor(cover float64) string {
+	if colors := c.coverageBadgeColors(); len(colors) > 0 {
+		if cl, err := matchBadgeColor(colors, parseCoverageThreshold, cover, false); err == nil {
+			return cl
+		}
+	}
 	switch {
 	case cover >= 80.0:
 		return green
@@ -328,6 +548,11 @@ func (c *Config) CoverageColor(cover float64) string {
 }
 
 func (c *Config) CodeToTestRatioColor(ratio float64) string {
+	if colors := c.codeToTestRatioBadgeColors(); len(colors) > 0 {
+		if cl, err := matchBadgeColor(colors, parseCodeToTestRatioThreshold, ratio, false); err == nil {
+			return cl
+		}
+	}
 	switch {
 	case ratio >= 1.2:
 		return green
@@ -343,6 +568,11 @@ func (c *Config) CodeToTestRatioColor(ratio float64) string {
 }
 
 func (c *Config) TestExecutionTimeColor(d time.Duration) string {
+	if colors := c.testExecutionTimeBadgeColors(); len(colors) > 0 {
+		if cl, err := matchBadgeColor(colors, parseTestExecutionTimeThreshold, float64(d), true); err == nil {
+			return cl
+		}
+	}
 	switch {
 	case d < 5*time.Minute:
 		return green
@@ -357,6 +587,81 @@ func (c *Config) TestExecutionTimeColor(d time.Duration) string {
 	}
 }
 
+// CentralSummaryThresholds returns the coverage thresholds (%) to count the repositories below them ( nil if not set ).
+func (c *Config) CentralSummaryThresholds() ([]float64, error) {
+	if c.Central == nil || c.Central.Summary == nil {
+		return nil, nil
+	}
+	thresholds := []float64{}
+	for _, t := range c.Central.Summary.Thresholds {
+		th, err := parseCoverageThreshold(t)
+		if err != nil {
+			return nil, fmt.Errorf("central.summary.thresholds: invalid threshold (%s)", t)
+		}
+		thresholds = append(thresholds, th)
+	}
+	return thresholds, nil
+}
+
+// CentralFeedThresholds returns the thresholds of the changes of the metrics ( coverage, ratio and time ) regarded as significant.
+// The change of coverage by defaultCentralFeedCoverageThreshold points is significant if no thresholds are set.
+func (c *Config) CentralFeedThresholds() (map[string]float64, error) {
+	if c.Central == nil || c.Central.Feed == nil || c.Central.Feed.Thresholds == nil {
+		return map[string]float64{"coverage": defaultCentralFeedCoverageThreshold}, nil
+	}
+	t := c.Central.Feed.Thresholds
+	thresholds := map[string]float64{}
+	if t.Coverage != "" {
+		th, err := parseCoverageThreshold(t.Coverage)
+		if err != nil || th < 0 {
+			return nil, fmt.Errorf("central.feed.thresholds.coverage: invalid threshold (%s)", t.Coverage)
+		}
+		thresholds["coverage"] = th
+	}
+	if t.CodeToTestRatio != "" {
+		th, err := strconv.ParseFloat(strings.TrimSpace(t.CodeToTestRatio), 64)
+		if err != nil || th < 0 {
+			return nil, fmt.Errorf("central.feed.thresholds.codeToTestRatio: invalid threshold (%s)", t.CodeToTestRatio)
+		}
+		thresholds["ratio"] = th
+	}
+	if t.TestExecutionTime != "" {
+		th, err := duration.Parse(t.TestExecutionTime)
+		if err != nil || th < 0 {
+			return nil, fmt.Errorf("central.feed.thresholds.testExecutionTime: invalid threshold (%s)", t.TestExecutionTime)
+		}
+		thresholds["time"] = float64(th)
+	}
+	if len(thresholds) == 0 {
+		thresholds["coverage"] = defaultCentralFeedCoverageThreshold
+	}
+	return thresholds, nil
+}
+
+// CentralStaleAfter returns the duration after which the report is regarded as stale ( 0 if not set ).
+func (c *Config) CentralStaleAfter() (time.Duration, error) {
+	if c.Central == nil || c.Central.StaleAfter == "" {
+		return 0, nil
+	}
+	d, err := duration.Parse(c.Central.StaleAfter)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("central.staleAfter: invalid duration (%s)", c.Central.StaleAfter)
+	}
+	return d, nil
+}
+
+// ReportRetentionWithin returns the duration within which all reports are kept ( 0 if not set ).
+func (c *Config) ReportRetentionWithin() (time.Duration, error) {
+	if c.Report == nil || c.Report.Retention == nil || c.Report.Retention.Within == "" {
+		return 0, nil
+	}
+	d, err := duration.Parse(c.Report.Retention.Within)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("report.retention.within: invalid duration (%s)", c.Report.Retention.Within)
+	}
+	return d, nil
+}
+
 func (c *Config) CheckIf(cond string) (bool, error) {
 	if cond == "" {
 		return true, nil
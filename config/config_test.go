@@ -7,7 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/ratio"
+	"github.com/k1LoW/octocov/report"
 )
 
 func TestMain(m *testing.M) {
@@ -59,6 +63,63 @@ func TestLoadConfigAndOmitEnableFlag(t *testing.T) {
 	}
 }
 
+func TestLoadWebhookBodyWithoutExpandingEnv(t *testing.T) {
+	t.Setenv("WEBHOOK_URL", "https://example.com/webhook")
+	t.Setenv("r", "expanded")
+	wd := t.TempDir()
+	y := `notify:
+  webhooks:
+    -
+      url: ${WEBHOOK_URL}
+      body: |
+        {{ $r := .Report }}{"text": "${r} {{ $r.Repository }}"}
+`
+	if err := os.WriteFile(filepath.Join(wd, ".octocov.yml"), []byte(y), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.wd = wd
+	if err := c.Load(""); err != nil {
+		t.Fatal(err)
+	}
+	w := c.Notify.Webhooks[0]
+	if want := "https://example.com/webhook"; w.URL != want {
+		t.Errorf("got %v\nwant %v", w.URL, want)
+	}
+	if want := "{{ $r := .Report }}{\"text\": \"${r} {{ $r.Repository }}\"}\n"; w.Body != want {
+		t.Errorf("got %v\nwant %v", w.Body, want)
+	}
+}
+
+func TestBuildCommentKey(t *testing.T) {
+	tests := []struct {
+		wd   string
+		path string
+		key  string
+		want string
+	}{
+		// not keyed unless comment.key is set, to keep matching the comments posted without the key
+		{filepath.Join(testdataDir(t), "config"), ".octocov.yml", "", ""},
+		{filepath.Join(testdataDir(t), "config"), ".octocov.yml", "matrix-1", "matrix-1"},
+		{filepath.Dir(testdataDir(t)), "", "", ""},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.wd = tt.wd
+		if err := c.Load(tt.path); err != nil {
+			t.Fatal(err)
+		}
+		if c.Comment == nil {
+			c.Comment = &ConfigComment{}
+		}
+		c.Comment.Key = tt.key
+		c.Build()
+		if got := c.Comment.Key; got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
 func TestCoverageAcceptable(t *testing.T) {
 	tests := []struct {
 		cond    string
@@ -178,6 +239,206 @@ func TestTestExecutionTimeAcceptable(t *testing.T) {
 	}
 }
 
+func TestMatchLabels(t *testing.T) {
+	r := &report.Report{
+		Coverage:        &coverage.Coverage{Total: 10, Covered: 4},
+		CodeToTestRatio: &ratio.Ratio{Code: 100, Test: 120},
+	}
+	rPrev := &report.Report{
+		Coverage:        &coverage.Coverage{Total: 10, Covered: 5},
+		CodeToTestRatio: &ratio.Ratio{Code: 100, Test: 100},
+	}
+	tests := []struct {
+		rules      []*ConfigLabelRule
+		wantAdd    []string
+		wantRemove []string
+		wantErr    bool
+	}{
+		{
+			[]*ConfigLabelRule{
+				{Name: "coverage:down", Condition: "diff < -1"},
+				{Name: "coverage:up", Condition: "diff > 0"},
+				{Name: "needs-tests", Condition: "patch_coverage < 50%"},
+				{Name: "tests:added", Condition: "code_to_test_ratio_diff > 0"},
+			},
+			[]string{"coverage:down", "tests:added"},
+			[]string{"coverage:up", "needs-tests"},
+			false,
+		},
+		{
+			[]*ConfigLabelRule{
+				{Name: "coverage:changed", Condition: "diff > 0"},
+				{Name: "coverage:changed", Condition: "diff < 0"},
+				{Name: "tests:added", Condition: "code_to_test_ratio_diff > 0"},
+				{Name: "tests:added", Condition: "false"},
+			},
+			[]string{"coverage:changed", "tests:added"},
+			[]string{},
+			false,
+		},
+		{
+			[]*ConfigLabelRule{
+				{Name: "invalid", Condition: "unknown_variable >"},
+			},
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Labels = &ConfigLabels{Rules: tt.rules}
+		add, remove, err := c.MatchLabels(r, rPrev, nil)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if diff := cmp.Diff(add, tt.wantAdd, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+		if diff := cmp.Diff(remove, tt.wantRemove, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestMatchLabelsWithoutPreviousReport(t *testing.T) {
+	r := &report.Report{
+		Coverage:          &coverage.Coverage{Total: 10, Covered: 4},
+		CodeToTestRatio:   &ratio.Ratio{Code: 100, Test: 120},
+		TestExecutionTime: func() *float64 { v := float64(time.Minute); return &v }(),
+	}
+	c := New()
+	c.Labels = &ConfigLabels{Rules: []*ConfigLabelRule{
+		{Name: "coverage:up", Condition: "coverage_diff > 0"},
+		{Name: "tests:added", Condition: "code_to_test_ratio_diff > 0"},
+		{Name: "tests:slower", Condition: "test_execution_time_diff > 0"},
+	}}
+	add, remove, err := c.MatchLabels(r, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(add, []string{}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if diff := cmp.Diff(remove, []string{"coverage:up", "tests:added", "tests:slower"}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestCoverageColor(t *testing.T) {
+	colors := []*ConfigBadgeColor{
+		{Threshold: "90%", Color: "brightgreen"},
+		{Threshold: "70", Color: "#abc"},
+		{Color: "lightgrey"},
+	}
+	tests := []struct {
+		colors []*ConfigBadgeColor
+		cover  float64
+		want   string
+	}{
+		{nil, 85.0, green},
+		{nil, 10.0, red},
+		{colors, 95.0, "#4C1"},
+		{colors, 90.0, "#4C1"},
+		{colors, 85.0, "#ABC"},
+		{colors, 10.0, "#9F9F9F"},
+		{[]*ConfigBadgeColor{{Threshold: "50", Color: "blue"}}, 10.0, red},
+		{[]*ConfigBadgeColor{{Threshold: "50", Color: "invalid"}}, 85.0, green},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{Badge: ConfigCoverageBadge{Colors: tt.colors}}
+		if got := c.CoverageColor(tt.cover); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestBadgeColors(t *testing.T) {
+	shared := &ConfigBadge{Colors: &ConfigBadgeColors{
+		Coverage:          []*ConfigBadgeColor{{Threshold: "50%", Color: "blue"}, {Color: "lightgrey"}},
+		TestExecutionTime: []*ConfigBadgeColor{{Threshold: "1min", Color: "blue"}},
+	}}
+	tests := []struct {
+		badge    *ConfigBadge
+		coverage []*ConfigBadgeColor
+		cover    float64
+		want     string
+	}{
+		{shared, nil, 60.0, "#007EC6"},
+		{shared, nil, 10.0, "#9F9F9F"},
+		// coverage.badge.colors overrides badge.colors.coverage
+		{shared, []*ConfigBadgeColor{{Threshold: "50%", Color: "green"}}, 60.0, green},
+		{&ConfigBadge{Colors: &ConfigBadgeColors{}}, nil, 60.0, yellowgreen},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Badge = tt.badge
+		c.Coverage = &ConfigCoverage{Badge: ConfigCoverageBadge{Colors: tt.coverage}}
+		if got := c.CoverageColor(tt.cover); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+	c := New()
+	c.Badge = shared
+	if got, want := c.TestExecutionTimeColor(30*time.Second), "#007EC6"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.CodeToTestRatioColor(1.0), yellowgreen; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestCodeToTestRatioColor(t *testing.T) {
+	c := New()
+	c.CodeToTestRatio = &ConfigCodeToTestRatio{Badge: ConfigCodeToTestRatioBadge{Colors: []*ConfigBadgeColor{
+		{Threshold: "1:1.0", Color: "green"},
+		{Threshold: "0.5", Color: "ff0"},
+	}}}
+	tests := []struct {
+		ratio float64
+		want  string
+	}{
+		{1.0, green},
+		{0.7, "#FF0"},
+		{0.1, red},
+	}
+	for _, tt := range tests {
+		if got := c.CodeToTestRatioColor(tt.ratio); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestTestExecutionTimeColor(t *testing.T) {
+	c := New()
+	c.TestExecutionTime = &ConfigTestExecutionTime{Badge: ConfigTestExecutionTimeBadge{Colors: []*ConfigBadgeColor{
+		{Threshold: "30min", Color: "yellow"},
+		{Threshold: "10min", Color: "green"},
+		{Color: "red"},
+	}}}
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{5 * time.Minute, green},
+		{10 * time.Minute, yellow},
+		{29 * time.Minute, yellow},
+		{time.Hour, red},
+	}
+	for _, tt := range tests {
+		if got := c.TestExecutionTimeColor(tt.d); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
 func revertEnv(envCache []string) error {
 	if err := clearEnv(); err != nil {
 		return err
@@ -213,3 +474,68 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestCentralStaleAfter(t *testing.T) {
+	tests := []struct {
+		central *ConfigCentral
+		want    time.Duration
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{&ConfigCentral{}, 0, false},
+		{&ConfigCentral{StaleAfter: "30days"}, 30 * 24 * time.Hour, false},
+		{&ConfigCentral{StaleAfter: "2weeks"}, 14 * 24 * time.Hour, false},
+		{&ConfigCentral{StaleAfter: "-1day"}, 0, true},
+		{&ConfigCentral{StaleAfter: "soon"}, 0, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Central = tt.central
+		got, err := c.CentralStaleAfter()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCentralFeedThresholds(t *testing.T) {
+	tests := []struct {
+		central *ConfigCentral
+		want    map[string]float64
+		wantErr bool
+	}{
+		{nil, map[string]float64{"coverage": 2}, false},
+		{&ConfigCentral{Feed: &ConfigCentralFeed{}}, map[string]float64{"coverage": 2}, false},
+		{&ConfigCentral{Feed: &ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{}}}, map[string]float64{"coverage": 2}, false},
+		{&ConfigCentral{Feed: &ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{Coverage: "0.5%"}}}, map[string]float64{"coverage": 0.5}, false},
+		{&ConfigCentral{Feed: &ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{CodeToTestRatio: "0.1", TestExecutionTime: "1min"}}}, map[string]float64{"ratio": 0.1, "time": float64(time.Minute)}, false},
+		{&ConfigCentral{Feed: &ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{Coverage: "-1%"}}}, nil, true},
+		{&ConfigCentral{Feed: &ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{TestExecutionTime: "soon"}}}, nil, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Central = tt.central
+		got, err := c.CentralFeedThresholds()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
 
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
 )
 
+var groupNameRe = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
 func (c *Config) CoverageConfigReady() error {
 	if c.Coverage == nil {
 		return errors.New("coverage: is not set")
@@ -66,6 +73,12 @@ func (c *Config) CommentConfigReady() error {
 	if !internal.IsEnable(c.Comment.Enable) {
 		return errors.New("comment.enable: is false")
 	}
+	if c.Comment.Mode != "" && !internal.Contains(gh.CommentModes, c.Comment.Mode) {
+		return fmt.Errorf("comment.mode: invalid mode (%s)", c.Comment.Mode)
+	}
+	if strings.Contains(c.Comment.Key, "--") {
+		return fmt.Errorf("comment.key: must not contain `--` (%s)", c.Comment.Key)
+	}
 	if c.Repository == "" {
 		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
 	}
@@ -94,6 +107,89 @@ func (c *Config) CommentConfigReady() error {
 	return nil
 }
 
+func (c *Config) CommitCommentConfigReady() error {
+	if c.Comment == nil {
+		return errors.New("comment: is not set")
+	}
+	if !internal.IsEnable(c.Comment.Enable) {
+		return errors.New("comment.enable: is false")
+	}
+	if !c.Comment.Commit {
+		return errors.New("comment.commit: is false")
+	}
+	if err := c.defaultBranchReady(); err != nil {
+		return err
+	}
+	ok, err := c.CheckIf(c.Comment.If)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.Comment.If)
+	}
+	return nil
+}
+
+func (c *Config) AlertsIssueConfigReady() error {
+	if c.Alerts == nil || c.Alerts.Issue == nil {
+		return errors.New("alerts.issue: is not set")
+	}
+	if !internal.IsEnable(c.Alerts.Issue.Enable) {
+		return errors.New("alerts.issue.enable: is false")
+	}
+	if c.Alerts.Issue.Consecutive < 0 {
+		return fmt.Errorf("alerts.issue.consecutive: invalid number (%d)", c.Alerts.Issue.Consecutive)
+	}
+	if c.Alerts.Issue.Consecutive > 1 && (c.Diff == nil || len(c.Diff.Datastores) == 0) {
+		return errors.New("alerts.issue.consecutive: diff.datastores: is not set to read the previous reports")
+	}
+	if err := c.defaultBranchReady(); err != nil {
+		return err
+	}
+	ok, err := c.CheckIf(c.Alerts.Issue.If)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.Alerts.Issue.If)
+	}
+	return nil
+}
+
+// defaultBranchReady checks that the job is running on a push to the default branch.
+func (c *Config) defaultBranchReady() error {
+	if c.Repository == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	ctx := context.Background()
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	if c.gh == nil {
+		g, err := gh.New()
+		if err != nil {
+			return err
+		}
+		c.gh = g
+	}
+	if !strings.HasPrefix(os.Getenv("GITHUB_REF"), "refs/heads/") {
+		return fmt.Errorf("env %s is not a branch (%s)", "GITHUB_REF", os.Getenv("GITHUB_REF"))
+	}
+	defaultBranch, err := c.gh.GetDefaultBranch(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return err
+	}
+	b, err := c.gh.DetectCurrentBranch(ctx)
+	if err != nil {
+		return err
+	}
+	if b != defaultBranch {
+		return fmt.Errorf("current branch is not the default branch (%s)", b)
+	}
+	return nil
+}
+
 func (c *Config) CoverageBadgeConfigReady() error {
 	if err := c.CoverageConfigReady(); err != nil {
 		return err
@@ -137,6 +233,56 @@ func (c *Config) CentralConfigReady() error {
 	if len(c.Central.Reports.Datastores) == 0 {
 		return errors.New("central.reports.datastores is not set")
 	}
+	if c.Central.Reports.Concurrency < 0 {
+		return fmt.Errorf("central.reports.concurrency: invalid number (%d)", c.Central.Reports.Concurrency)
+	}
+	if err := c.BadgeStyleReady(); err != nil {
+		return err
+	}
+	if c.Central.Template != "" {
+		if _, err := os.Stat(c.Central.Template); err != nil {
+			return fmt.Errorf("central.template: %w", err)
+		}
+	}
+	if _, err := c.CentralSummaryThresholds(); err != nil {
+		return err
+	}
+	if _, err := c.CentralStaleAfter(); err != nil {
+		return err
+	}
+	if c.Central.Branches != nil {
+		for _, p := range c.Central.Branches.Tracked {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("central.branches.tracked: invalid pattern (%s)", p)
+			}
+		}
+	}
+	names := map[string]struct{}{}
+	for i, g := range c.Central.Groups {
+		if !groupNameRe.MatchString(g.Name) || strings.Trim(g.Name, ".") == "" || strings.Contains(g.Name, "..") {
+			return fmt.Errorf("central.groups[%d].name: invalid name (%s)", i, g.Name)
+		}
+		if _, ok := names[g.Name]; ok {
+			return fmt.Errorf("central.groups[%d].name: duplicate name (%s)", i, g.Name)
+		}
+		names[g.Name] = struct{}{}
+		if len(g.Repositories) == 0 && g.Topic == "" {
+			return fmt.Errorf("central.groups[%d]: repositories or topic is not set", i)
+		}
+		for _, p := range g.Repositories {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("central.groups[%d].repositories: invalid pattern (%s)", i, p)
+			}
+		}
+	}
+	for _, f := range c.Central.Badges.Formats {
+		if !internal.Contains(badge.Formats, f) {
+			return fmt.Errorf("central.badges.formats: invalid format (%s)", f)
+		}
+	}
+	if err := c.BadgeColorsReady(); err != nil {
+		return err
+	}
 	ok, err := c.CheckIf(c.Central.If)
 	if err != nil {
 		return err
@@ -147,6 +293,35 @@ func (c *Config) CentralConfigReady() error {
 	return nil
 }
 
+func (c *Config) CentralHTMLConfigReady() error {
+	if err := c.CentralConfigReady(); err != nil {
+		return err
+	}
+	if c.Central.HTML == nil {
+		return errors.New("central.html: is not set")
+	}
+	if !internal.IsEnable(c.Central.HTML.Enable) {
+		return errors.New("central.html.enable: is false")
+	}
+	return nil
+}
+
+func (c *Config) CentralFeedConfigReady() error {
+	if err := c.CentralConfigReady(); err != nil {
+		return err
+	}
+	if c.Central.Feed == nil {
+		return errors.New("central.feed: is not set")
+	}
+	if !internal.IsEnable(c.Central.Feed.Enable) {
+		return errors.New("central.feed.enable: is false")
+	}
+	if _, err := c.CentralFeedThresholds(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Config) CentralPushConfigReady() error {
 	if err := c.CentralConfigReady(); err != nil {
 		return err
@@ -187,6 +362,67 @@ func (c *Config) DiffConfigReady() error {
 	return nil
 }
 
+func (c *Config) NotifyConfigReady() error {
+	if c.Notify == nil {
+		return errors.New("notify: is not set")
+	}
+	if len(c.Notify.Webhooks) == 0 {
+		return errors.New("notify.webhooks: is not set")
+	}
+	for i, w := range c.Notify.Webhooks {
+		if w.URL == "" {
+			return fmt.Errorf("notify.webhooks[%d].url: is not set", i)
+		}
+	}
+	return nil
+}
+
+func (c *Config) LabelsConfigReady() error {
+	if c.Labels == nil {
+		return errors.New("labels: is not set")
+	}
+	if !internal.IsEnable(c.Labels.Enable) {
+		return errors.New("labels.enable: is false")
+	}
+	if len(c.Labels.Rules) == 0 {
+		return errors.New("labels.rules: is not set")
+	}
+	for i, r := range c.Labels.Rules {
+		if r.Name == "" {
+			return fmt.Errorf("labels.rules[%d].name: is not set", i)
+		}
+		if r.Condition == "" {
+			return fmt.Errorf("labels.rules[%d].condition: is not set", i)
+		}
+	}
+	if c.Repository == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	ctx := context.Background()
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	if c.gh == nil {
+		g, err := gh.New()
+		if err != nil {
+			return err
+		}
+		c.gh = g
+	}
+	if _, err := c.gh.DetectCurrentPullRequestNumber(ctx, repo.Owner, repo.Repo); err != nil {
+		return err
+	}
+	ok, err := c.CheckIf(c.Labels.If)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.Labels.If)
+	}
+	return nil
+}
+
 func (c *Config) ReportConfigReady() error {
 	if err := c.ReportConfigTargetReady(); err != nil {
 		return err
@@ -201,6 +437,28 @@ func (c *Config) ReportConfigReady() error {
 	return nil
 }
 
+func (c *Config) ReportRetentionConfigReady() error {
+	if c.Report == nil {
+		return errors.New("report: is not set")
+	}
+	if len(c.Report.Datastores) == 0 {
+		return errors.New("report.datastores: is not set")
+	}
+	if c.Report.Retention == nil {
+		return errors.New("report.retention: is not set")
+	}
+	if c.Report.Retention.Last < 0 {
+		return fmt.Errorf("report.retention.last: invalid number (%d)", c.Report.Retention.Last)
+	}
+	if _, err := c.ReportRetentionWithin(); err != nil {
+		return err
+	}
+	if c.Report.Retention.Last == 0 && c.Report.Retention.Within == "" && !c.Report.Retention.Weekly {
+		return errors.New("report.retention: no rules are set")
+	}
+	return nil
+}
+
 func (c *Config) ReportConfigTargetReady() error {
 	if c.Report == nil {
 		return errors.New("report: is not set")
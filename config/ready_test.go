@@ -266,6 +266,26 @@ func TestCommentConfigReady(t *testing.T) {
 			},
 			"the condition in the `if` section is not met (false)",
 		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Comment: &ConfigComment{
+					Mode: "update",
+				},
+				gh: mg,
+			},
+			"",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Comment: &ConfigComment{
+					Mode: "overwrite",
+				},
+				gh: mg,
+			},
+			"comment.mode: invalid mode (overwrite)",
+		},
 	}
 	for _, tt := range tests {
 		err := tt.c.CommentConfigReady()
@@ -285,6 +305,244 @@ func TestCommentConfigReady(t *testing.T) {
 	}
 }
 
+func TestCommitCommentConfigReady(t *testing.T) {
+	os.Setenv("GITHUB_EVENT_NAME", "push")
+	os.Setenv("GITHUB_EVENT_PATH", filepath.Join(testdataDir(t), "config", "event_pull_request_opened.json"))
+	tests := []struct {
+		ref  string
+		c    *Config
+		want string
+	}{
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Comment:    &ConfigComment{},
+				gh:         mockedGh(t),
+			},
+			"comment.commit: is false",
+		},
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Comment: &ConfigComment{
+					Commit: true,
+				},
+				gh: mockedGh(t),
+			},
+			"",
+		},
+		{
+			"refs/heads/feature",
+			&Config{
+				Repository: "owner/repo",
+				Comment: &ConfigComment{
+					Commit: true,
+				},
+				gh: mockedGh(t),
+			},
+			"current branch is not the default branch (feature)",
+		},
+		{
+			"refs/pull/123/merge",
+			&Config{
+				Repository: "owner/repo",
+				Comment: &ConfigComment{
+					Commit: true,
+				},
+				gh: mockedGh(t),
+			},
+			"env GITHUB_REF is not a branch (refs/pull/123/merge)",
+		},
+	}
+	for _, tt := range tests {
+		os.Setenv("GITHUB_REF", tt.ref)
+		err := tt.c.CommitCommentConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestAlertsIssueConfigReady(t *testing.T) {
+	os.Setenv("GITHUB_EVENT_NAME", "push")
+	os.Setenv("GITHUB_EVENT_PATH", filepath.Join(testdataDir(t), "config", "event_pull_request_opened.json"))
+	tests := []struct {
+		ref  string
+		c    *Config
+		want string
+	}{
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				gh:         mockedGh(t),
+			},
+			"alerts.issue: is not set",
+		},
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Alerts: &ConfigAlerts{
+					Issue: &ConfigAlertsIssue{
+						Enable: internal.Bool(false),
+					},
+				},
+				gh: mockedGh(t),
+			},
+			"alerts.issue.enable: is false",
+		},
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Alerts: &ConfigAlerts{
+					Issue: &ConfigAlertsIssue{
+						Enable: internal.Bool(true),
+					},
+				},
+				gh: mockedGh(t),
+			},
+			"",
+		},
+		{
+			"refs/heads/feature",
+			&Config{
+				Repository: "owner/repo",
+				Alerts: &ConfigAlerts{
+					Issue: &ConfigAlertsIssue{},
+				},
+				gh: mockedGh(t),
+			},
+			"current branch is not the default branch (feature)",
+		},
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Alerts: &ConfigAlerts{
+					Issue: &ConfigAlertsIssue{
+						Consecutive: 3,
+					},
+				},
+				gh: mockedGh(t),
+			},
+			"alerts.issue.consecutive: diff.datastores: is not set to read the previous reports",
+		},
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Alerts: &ConfigAlerts{
+					Issue: &ConfigAlertsIssue{
+						Consecutive: 3,
+					},
+				},
+				Diff: &ConfigDiff{
+					Datastores: []string{"local://reports"},
+				},
+				gh: mockedGh(t),
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		os.Setenv("GITHUB_REF", tt.ref)
+		err := tt.c.AlertsIssueConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestLabelsConfigReady(t *testing.T) {
+	os.Setenv("GITHUB_REF", "refs/pull/123/merge")
+	os.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	os.Setenv("GITHUB_EVENT_PATH", filepath.Join(testdataDir(t), "config", "event_pull_request_opened.json"))
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{
+				Repository: "owner/repo",
+				gh:         mockedGh(t),
+			},
+			"labels: is not set",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Labels:     &ConfigLabels{},
+				gh:         mockedGh(t),
+			},
+			"labels.rules: is not set",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Labels: &ConfigLabels{
+					Rules: []*ConfigLabelRule{
+						{Name: "coverage:down"},
+					},
+				},
+				gh: mockedGh(t),
+			},
+			"labels.rules[0].condition: is not set",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Labels: &ConfigLabels{
+					Rules: []*ConfigLabelRule{
+						{Name: "coverage:down", Condition: "diff < -1"},
+					},
+				},
+				gh: mockedGh(t),
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.LabelsConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
 func TestCoverageBadgeConfigReady(t *testing.T) {
 	tests := []struct {
 		c    *Config
@@ -296,69 +554,501 @@ func TestCoverageBadgeConfigReady(t *testing.T) {
 					Paths: []string{"path/to/coverage.xml"},
 				},
 			},
-			"coverage.badge.path: is not set",
+			"coverage.badge.path: is not set",
+		},
+		{
+			&Config{
+				Coverage: &ConfigCoverage{
+					Paths: []string{"path/to/coverage.xml"},
+					Badge: ConfigCoverageBadge{
+						Path: "path/to/coverage.svg",
+					},
+				},
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.CoverageBadgeConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestBadgeStyleReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{&Config{}, ""},
+		{&Config{Badge: &ConfigBadge{Style: "for-the-badge"}}, ""},
+		{&Config{Badge: &ConfigBadge{Style: "popout"}}, "badge.style: invalid style (popout)"},
+	}
+	for _, tt := range tests {
+		err := tt.c.BadgeStyleReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestBadgeColorsReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{&Config{}, ""},
+		{
+			&Config{
+				Coverage: &ConfigCoverage{
+					Badge: ConfigCoverageBadge{
+						Colors: []*ConfigBadgeColor{
+							{Threshold: "80%", Color: "green"},
+							{Color: "#e05d44"},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			&Config{
+				Coverage: &ConfigCoverage{
+					Paths: []string{"path/to/coverage.xml"},
+					Badge: ConfigCoverageBadge{
+						Path: "path/to/coverage.svg",
+						Colors: []*ConfigBadgeColor{
+							{Threshold: "80%", Color: "green"},
+							{Threshold: "high", Color: "yellow"},
+						},
+					},
+				},
+			},
+			"coverage.badge.colors[1].threshold: invalid threshold (high)",
+		},
+		{
+			&Config{
+				Coverage: &ConfigCoverage{
+					Badge: ConfigCoverageBadge{
+						Colors: []*ConfigBadgeColor{
+							{Threshold: "80%", Color: "greenish"},
+						},
+					},
+				},
+			},
+			"coverage.badge.colors[0].color: invalid color: greenish",
+		},
+		{
+			&Config{
+				CodeToTestRatio: &ConfigCodeToTestRatio{
+					Badge: ConfigCodeToTestRatioBadge{
+						Colors: []*ConfigBadgeColor{
+							{Threshold: "1:x", Color: "green"},
+						},
+					},
+				},
+			},
+			"codeToTestRatio.badge.colors[0].threshold: invalid threshold (1:x)",
+		},
+		{
+			&Config{
+				TestExecutionTime: &ConfigTestExecutionTime{
+					Badge: ConfigTestExecutionTimeBadge{
+						Colors: []*ConfigBadgeColor{
+							{Threshold: "1min", Color: "bleu"},
+						},
+					},
+				},
+			},
+			"testExecutionTime.badge.colors[0].color: invalid color: bleu",
+		},
+		{
+			&Config{
+				Badge: &ConfigBadge{
+					Colors: &ConfigBadgeColors{
+						CodeToTestRatio: []*ConfigBadgeColor{
+							{Threshold: "1:1.0", Color: "grene"},
+						},
+					},
+				},
+			},
+			"badge.colors.codeToTestRatio[0].color: invalid color: grene",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.BadgeColorsReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestCodeToTestRatioBadgeConfigReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{
+				CodeToTestRatio: &ConfigCodeToTestRatio{
+					Test: []string{
+						"**_test.go",
+					},
+				},
+			},
+			"codeToTestRatio.badge.path: is not set",
+		},
+		{
+			&Config{
+				CodeToTestRatio: &ConfigCodeToTestRatio{
+					Test: []string{
+						"**_test.go",
+					},
+					Badge: ConfigCodeToTestRatioBadge{
+						Path: "path/to/ratio.svg",
+					},
+				},
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.CodeToTestRatioBadgeConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestTestExecutionTimeBadgeConfigReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{
+				TestExecutionTime: &ConfigTestExecutionTime{
+					Steps: []string{
+						"Run tests",
+					},
+				},
+			},
+			"testExecutionTime.badge.path: is not set",
+		},
+		{
+			&Config{
+				TestExecutionTime: &ConfigTestExecutionTime{
+					Steps: []string{
+						"Run tests",
+					},
+					Badge: ConfigTestExecutionTimeBadge{
+						Path: "path/to/time.svg",
+					},
+				},
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.TestExecutionTimeBadgeConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestCentralConfigReady(t *testing.T) {
+	mg := mockedGh(t)
+
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{},
+			"central: is not set",
+		},
+		{
+			&Config{
+				Central: &ConfigCentral{},
+				gh:      mockedGh(t),
+			},
+			"repository: not set (or env GITHUB_REPOSITORY is not set)",
+		},
+		{
+			&Config{
+				Central: &ConfigCentral{
+					Enable: internal.Bool(false),
+				},
+				gh: mg,
+			},
+			"central.enable: is false",
+		},
+		{
+			&Config{
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+				},
+				gh: mg,
+			},
+			"repository: not set (or env GITHUB_REPOSITORY is not set)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+				},
+				gh: mg,
+			},
+			"central.reports.datastores is not set",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+				},
+				gh: mg,
+			},
+			"",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Badges: ConfigCentralBadges{
+						Formats: []string{"svg", "gif"},
+					},
+				},
+				gh: mg,
+			},
+			"central.badges.formats: invalid format (gif)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Summary: &ConfigCentralSummary{
+						Thresholds: []string{"80%", "high"},
+					},
+				},
+				gh: mg,
+			},
+			"central.summary.thresholds: invalid threshold (high)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+						Concurrency: -1,
+					},
+				},
+				gh: mg,
+			},
+			"central.reports.concurrency: invalid number (-1)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Branches: &ConfigCentralBranches{
+						Tracked: []string{"release/*", "[main"},
+					},
+				},
+				gh: mg,
+			},
+			"central.branches.tracked: invalid pattern ([main)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Groups: []*ConfigCentralGroup{
+						{Name: "team a", Topic: "team-a"},
+					},
+				},
+				gh: mg,
+			},
+			"central.groups[0].name: invalid name (team a)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Groups: []*ConfigCentralGroup{
+						{Name: "a..b", Topic: "team-a"},
+					},
+				},
+				gh: mg,
+			},
+			"central.groups[0].name: invalid name (a..b)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Groups: []*ConfigCentralGroup{
+						{Name: "team/a", Topic: "team-a"},
+					},
+				},
+				gh: mg,
+			},
+			"central.groups[0].name: invalid name (team/a)",
 		},
 		{
 			&Config{
-				Coverage: &ConfigCoverage{
-					Paths: []string{"path/to/coverage.xml"},
-					Badge: ConfigCoverageBadge{
-						Path: "path/to/coverage.svg",
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Groups: []*ConfigCentralGroup{
+						{Name: "team-a", Topic: "team-a"},
+						{Name: "team-a", Repositories: []string{"owner/*"}},
 					},
 				},
+				gh: mg,
 			},
-			"",
+			"central.groups[1].name: duplicate name (team-a)",
 		},
-	}
-	for _, tt := range tests {
-		err := tt.c.CoverageBadgeConfigReady()
-		if err == nil && tt.want != "" {
-			t.Errorf("got %v\nwant %v", err, tt.want)
-			continue
-		}
-		if err != nil && tt.want == "" {
-			t.Errorf("got %v\nwant %v", err, tt.want)
-			continue
-		}
-		if err != nil && tt.want != "" {
-			if got := err.Error(); got != tt.want {
-				t.Errorf("got %v\nwant %v", got, tt.want)
-			}
-		}
-	}
-}
-
-func TestCodeToTestRatioBadgeConfigReady(t *testing.T) {
-	tests := []struct {
-		c    *Config
-		want string
-	}{
 		{
 			&Config{
-				CodeToTestRatio: &ConfigCodeToTestRatio{
-					Test: []string{
-						"**_test.go",
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
+					},
+					Groups: []*ConfigCentralGroup{
+						{Name: "team-a"},
 					},
 				},
+				gh: mg,
 			},
-			"codeToTestRatio.badge.path: is not set",
+			"central.groups[0]: repositories or topic is not set",
 		},
 		{
 			&Config{
-				CodeToTestRatio: &ConfigCodeToTestRatio{
-					Test: []string{
-						"**_test.go",
-					},
-					Badge: ConfigCodeToTestRatioBadge{
-						Path: "path/to/ratio.svg",
+				Repository: "owner/repo",
+				Central: &ConfigCentral{
+					Enable: internal.Bool(true),
+					Reports: ConfigCentralReports{
+						Datastores: []string{
+							"s3://bucket/reports",
+						},
 					},
+					If: "false",
 				},
+				gh: mg,
 			},
-			"",
+			"the condition in the `if` section is not met (false)",
 		},
 	}
 	for _, tt := range tests {
-		err := tt.c.CodeToTestRatioBadgeConfigReady()
+		err := tt.c.CentralConfigReady()
 		if err == nil && tt.want != "" {
 			t.Errorf("got %v\nwant %v", err, tt.want)
 			continue
@@ -375,37 +1065,38 @@ func TestCodeToTestRatioBadgeConfigReady(t *testing.T) {
 	}
 }
 
-func TestTestExecutionTimeBadgeConfigReady(t *testing.T) {
+func TestCentralHTMLConfigReady(t *testing.T) {
+	mg := mockedGh(t)
+	central := func(html *ConfigCentralHTML) *ConfigCentral {
+		return &ConfigCentral{
+			Enable: internal.Bool(true),
+			Reports: ConfigCentralReports{
+				Datastores: []string{
+					"s3://bucket/reports",
+				},
+			},
+			HTML: html,
+		}
+	}
 	tests := []struct {
 		c    *Config
 		want string
 	}{
 		{
-			&Config{
-				TestExecutionTime: &ConfigTestExecutionTime{
-					Steps: []string{
-						"Run tests",
-					},
-				},
-			},
-			"testExecutionTime.badge.path: is not set",
+			&Config{Repository: "owner/repo", Central: central(nil), gh: mg},
+			"central.html: is not set",
 		},
 		{
-			&Config{
-				TestExecutionTime: &ConfigTestExecutionTime{
-					Steps: []string{
-						"Run tests",
-					},
-					Badge: ConfigTestExecutionTimeBadge{
-						Path: "path/to/time.svg",
-					},
-				},
-			},
+			&Config{Repository: "owner/repo", Central: central(&ConfigCentralHTML{Enable: internal.Bool(false)}), gh: mg},
+			"central.html.enable: is false",
+		},
+		{
+			&Config{Repository: "owner/repo", Central: central(&ConfigCentralHTML{}), gh: mg},
 			"",
 		},
 	}
 	for _, tt := range tests {
-		err := tt.c.TestExecutionTimeBadgeConfigReady()
+		err := tt.c.CentralHTMLConfigReady()
 		if err == nil && tt.want != "" {
 			t.Errorf("got %v\nwant %v", err, tt.want)
 			continue
@@ -422,86 +1113,46 @@ func TestTestExecutionTimeBadgeConfigReady(t *testing.T) {
 	}
 }
 
-func TestCentralConfigReady(t *testing.T) {
+func TestCentralFeedConfigReady(t *testing.T) {
 	mg := mockedGh(t)
-
+	central := func(feed *ConfigCentralFeed) *ConfigCentral {
+		return &ConfigCentral{
+			Enable: internal.Bool(true),
+			Reports: ConfigCentralReports{
+				Datastores: []string{
+					"s3://bucket/reports",
+				},
+			},
+			Feed: feed,
+		}
+	}
 	tests := []struct {
 		c    *Config
 		want string
 	}{
 		{
-			&Config{},
-			"central: is not set",
-		},
-		{
-			&Config{
-				Central: &ConfigCentral{},
-				gh:      mockedGh(t),
-			},
-			"repository: not set (or env GITHUB_REPOSITORY is not set)",
-		},
-		{
-			&Config{
-				Central: &ConfigCentral{
-					Enable: internal.Bool(false),
-				},
-				gh: mg,
-			},
-			"central.enable: is false",
+			&Config{Repository: "owner/repo", Central: central(nil), gh: mg},
+			"central.feed: is not set",
 		},
 		{
-			&Config{
-				Central: &ConfigCentral{
-					Enable: internal.Bool(true),
-				},
-				gh: mg,
-			},
-			"repository: not set (or env GITHUB_REPOSITORY is not set)",
+			&Config{Repository: "owner/repo", Central: central(&ConfigCentralFeed{Enable: internal.Bool(false)}), gh: mg},
+			"central.feed.enable: is false",
 		},
 		{
-			&Config{
-				Repository: "owner/repo",
-				Central: &ConfigCentral{
-					Enable: internal.Bool(true),
-				},
-				gh: mg,
-			},
-			"central.reports.datastores is not set",
+			&Config{Repository: "owner/repo", Central: central(&ConfigCentralFeed{}), gh: mg},
+			"",
 		},
 		{
-			&Config{
-				Repository: "owner/repo",
-				Central: &ConfigCentral{
-					Enable: internal.Bool(true),
-					Reports: ConfigCentralReports{
-						Datastores: []string{
-							"s3://bucket/reports",
-						},
-					},
-				},
-				gh: mg,
-			},
+			&Config{Repository: "owner/repo", Central: central(&ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{Coverage: "1.5%", TestExecutionTime: "30sec"}}), gh: mg},
 			"",
 		},
 		{
-			&Config{
-				Repository: "owner/repo",
-				Central: &ConfigCentral{
-					Enable: internal.Bool(true),
-					Reports: ConfigCentralReports{
-						Datastores: []string{
-							"s3://bucket/reports",
-						},
-					},
-					If: "false",
-				},
-				gh: mg,
-			},
-			"the condition in the `if` section is not met (false)",
+			&Config{Repository: "owner/repo", Central: central(&ConfigCentralFeed{Thresholds: &ConfigCentralFeedThresholds{CodeToTestRatio: "much"}}), gh: mg},
+			"central.feed.thresholds.codeToTestRatio: invalid threshold (much)",
 		},
 	}
 	for _, tt := range tests {
-		err := tt.c.CentralConfigReady()
+		err := tt.c.CentralFeedConfigReady()
 		if err == nil && tt.want != "" {
 			t.Errorf("got %v\nwant %v", err, tt.want)
 			continue
@@ -679,6 +1330,61 @@ func TestDiffConfigReady(t *testing.T) {
 	}
 }
 
+func TestNotifyConfigReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{},
+			"notify: is not set",
+		},
+		{
+			&Config{
+				Notify: &ConfigNotify{},
+			},
+			"notify.webhooks: is not set",
+		},
+		{
+			&Config{
+				Notify: &ConfigNotify{
+					Webhooks: []*ConfigNotifyWebhook{
+						{URL: "https://hooks.example.com/xxx"},
+						{},
+					},
+				},
+			},
+			"notify.webhooks[1].url: is not set",
+		},
+		{
+			&Config{
+				Notify: &ConfigNotify{
+					Webhooks: []*ConfigNotifyWebhook{
+						{URL: "https://hooks.example.com/xxx"},
+					},
+				},
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.NotifyConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
 func TestReportConfigReady(t *testing.T) {
 	os.Setenv("GITHUB_EVENT_NAME", "pull_request")
 	os.Setenv("GITHUB_EVENT_PATH", filepath.Join(testdataDir(t), "config", "event_pull_request_opened.json"))
@@ -747,6 +1453,66 @@ func TestReportConfigReady(t *testing.T) {
 	}
 }
 
+func TestReportRetentionConfigReady(t *testing.T) {
+	report := func(rt *ConfigReportRetention) *ConfigReport {
+		return &ConfigReport{
+			Datastores: []string{
+				"s3://bucket/reports",
+			},
+			Retention: rt,
+		}
+	}
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{},
+			"report: is not set",
+		},
+		{
+			&Config{Report: &ConfigReport{Path: "report.json"}},
+			"report.datastores: is not set",
+		},
+		{
+			&Config{Report: report(nil)},
+			"report.retention: is not set",
+		},
+		{
+			&Config{Report: report(&ConfigReportRetention{})},
+			"report.retention: no rules are set",
+		},
+		{
+			&Config{Report: report(&ConfigReportRetention{Last: -1})},
+			"report.retention.last: invalid number (-1)",
+		},
+		{
+			&Config{Report: report(&ConfigReportRetention{Within: "long"})},
+			"report.retention.within: invalid duration (long)",
+		},
+		{
+			&Config{Report: report(&ConfigReportRetention{Last: 10, Within: "30days", Weekly: true})},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.ReportRetentionConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
 func mockedGh(t *testing.T) *gh.Gh {
 	mockedHTTPClient := mock.NewMockedHTTPClient(
 		mock.WithRequestMatch(
@@ -1,7 +1,15 @@
 package config
 
 type ConfigReport struct {
-	If         string   `yaml:"if,omitempty"`
-	Path       string   `yaml:"path,omitempty"`
-	Datastores []string `yaml:"datastores,omitempty"`
+	If         string                 `yaml:"if,omitempty"`
+	Path       string                 `yaml:"path,omitempty"`
+	Datastores []string               `yaml:"datastores,omitempty"`
+	History    bool                   `yaml:"history,omitempty"`
+	Retention  *ConfigReportRetention `yaml:"retention,omitempty"`
+}
+
+type ConfigReportRetention struct {
+	Last   int    `yaml:"last,omitempty"`
+	Within string `yaml:"within,omitempty"`
+	Weekly bool   `yaml:"weekly,omitempty"`
 }
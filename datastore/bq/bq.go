@@ -113,6 +113,55 @@ func (b *BQ) Put(ctx context.Context, path string, context []byte) error {
 	return errors.New("not implemented")
 }
 
+// Delete is not supported because the reports are not stored as files.
+// `octocov gc` deletes the rows of the reports with DeleteReports instead.
+func (b *BQ) Delete(ctx context.Context, paths []string) error {
+	return errors.New("not implemented")
+}
+
+// ListReports returns the rows ( without raw ) of the reports of the repository.
+func (b *BQ) ListReports(ctx context.Context, owner, repo string) ([]*ReportRecord, error) {
+	q := b.client.Query(fmt.Sprintf("SELECT id, owner, repo, ref, commit, timestamp FROM `%s.%s` WHERE owner = @owner AND repo = @repo", b.dataset, b.table)) // #nosec
+	q.Parameters = []bigquery.QueryParameter{
+		{Name: "owner", Value: owner},
+		{Name: "repo", Value: repo},
+	}
+	it, err := q.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := []*ReportRecord{}
+	for {
+		var rr ReportRecord
+		err := it.Next(&rr)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &rr)
+	}
+	return records, nil
+}
+
+// DeleteReports deletes the rows of the reports by id.
+func (b *BQ) DeleteReports(ctx context.Context, ids []string) error {
+	q := b.client.Query(fmt.Sprintf("DELETE FROM `%s.%s` WHERE id IN UNNEST(@ids)", b.dataset, b.table)) // #nosec
+	q.Parameters = []bigquery.QueryParameter{
+		{Name: "ids", Value: ids},
+	}
+	job, err := q.Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
+
 func (b *BQ) CreateTable(ctx context.Context) error {
 	metaData := &bigquery.TableMetadata{
 		Schema: reportsSchema,
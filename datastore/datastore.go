@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
@@ -34,6 +35,7 @@ var (
 
 type Datastore interface {
 	Put(ctx context.Context, path string, content []byte) error
+	Delete(ctx context.Context, paths []string) error
 	StoreReport(ctx context.Context, r *report.Report) error
 	FS() (fs.FS, error)
 }
@@ -106,6 +108,54 @@ func New(ctx context.Context, u, configRoot string) (Datastore, error) {
 	return nil, fmt.Errorf("invalid datastore: %s", u)
 }
 
+// HistoryPath returns the path where the report is kept as history.
+func HistoryPath(r *report.Report) string {
+	commit := r.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("%s/history/%s-%s.json", r.Repository, r.Timestamp.UTC().Format("20060102T150405Z"), commit)
+}
+
+// StoreReportHistory stores the report as history in addition to report.json.
+func StoreReportHistory(ctx context.Context, d Datastore, r *report.Report) error {
+	if _, ok := d.(*bq.BQ); ok {
+		// BigQuery keeps every stored report as a row.
+		return nil
+	}
+	return d.Put(ctx, HistoryPath(r), r.Bytes())
+}
+
+// ReadReports reads all reports in the directory of the repository.
+func ReadReports(fsys fs.FS, dir string) ([]*report.Report, error) {
+	reports := []*report.Report{}
+	if err := fs.WalkDir(fsys, dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		b, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil
+		}
+		r := &report.Report{}
+		if err := json.Unmarshal(b, r); err != nil {
+			return nil
+		}
+		if r.Repository == "" || r.Timestamp.IsZero() {
+			// not a report
+			return nil
+		}
+		reports = append(reports, r)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
 func parse(u, configRoot string) (datastore string, args []string, err error) {
 	switch {
 	case strings.HasPrefix(u, "github://"):
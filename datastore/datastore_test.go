@@ -4,8 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/report"
 )
 
 func TestParse(t *testing.T) {
@@ -73,3 +76,37 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestHistoryPath(t *testing.T) {
+	r := &report.Report{
+		Repository: "owner/repo",
+		Commit:     "1234567890abcdef",
+		Timestamp:  time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+	want := "owner/repo/history/20060102T150405Z-1234567.json"
+	if got := HistoryPath(r); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestReadReports(t *testing.T) {
+	fsys := fstest.MapFS{
+		"owner/repo/report.json":                                &fstest.MapFile{Data: []byte(`{"repository":"owner/repo","commit":"c","timestamp":"2006-01-03T15:04:05Z"}`)},
+		"owner/repo/history/20060102T150405Z-b.json":            &fstest.MapFile{Data: []byte(`{"repository":"owner/repo","commit":"b","timestamp":"2006-01-02T15:04:05Z"}`)},
+		"owner/repo/history/invalid.json":                       &fstest.MapFile{Data: []byte(`invalid`)},
+		"owner/repo/coverage.svg":                               &fstest.MapFile{Data: []byte(`<svg></svg>`)},
+		"owner/other/report.json":                               &fstest.MapFile{Data: []byte(`{"repository":"owner/other","commit":"a","timestamp":"2006-01-01T15:04:05Z"}`)},
+		"owner/repo/history/20060101T150405Z-empty-report.json": &fstest.MapFile{Data: []byte(`{}`)},
+	}
+	got, err := ReadReports(fsys, "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := []string{}
+	for _, r := range got {
+		commits = append(commits, r.Commit)
+	}
+	if diff := cmp.Diff(commits, []string{"b", "c"}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
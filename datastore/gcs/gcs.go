@@ -42,6 +42,16 @@ func (g *GCS) Put(ctx context.Context, path string, content []byte) error {
 	return nil
 }
 
+func (g *GCS) Delete(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		o := filepath.Join(g.prefix, path)
+		if err := g.client.Bucket(g.bucket).Object(o).Delete(ctx); err != nil && err != storage.ErrObjectNotExist {
+			return err
+		}
+	}
+	return nil
+}
+
 type GCSFS struct {
 	prefix string
 	gscfs  *gcsfs.FS
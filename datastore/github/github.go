@@ -45,6 +45,19 @@ func (g *Github) Put(ctx context.Context, path string, content []byte) error {
 	return g.gh.PushContent(ctx, repo.Owner, repo.Repo, branch, string(content), cp, message)
 }
 
+func (g *Github) Delete(ctx context.Context, paths []string) error {
+	repo, err := gh.Parse(g.repository)
+	if err != nil {
+		return err
+	}
+	cps := []string{}
+	for _, path := range paths {
+		cps = append(cps, filepath.Join(g.prefix, path))
+	}
+	message := fmt.Sprintf("Delete %d coverage reports", len(paths))
+	return g.gh.DeleteContents(ctx, repo.Owner, repo.Repo, g.branch, cps, message)
+}
+
 func (g *Github) FS() (fs.FS, error) {
 	r, err := gh.Parse(g.repository)
 	if err != nil {
@@ -52,6 +52,15 @@ func (l *Local) Put(ctx context.Context, path string, content []byte) error {
 	return os.WriteFile(p, content, os.ModePerm)
 }
 
+func (l *Local) Delete(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		if err := os.Remove(filepath.Join(l.root, path)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (l *Local) FS() (fs.FS, error) {
 	return os.DirFS(l.root), nil
 }
@@ -0,0 +1,152 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/datastore/bq"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+)
+
+// Retention is the policy for the reports kept as history.
+// A report is kept if any of the rules keeps it, and the latest report of each ref is always kept.
+type Retention struct {
+	// Last is the number of the latest reports kept for each ref
+	Last int
+	// Within is the duration within which all reports are kept
+	Within time.Duration
+	// Weekly keeps the latest report of each week
+	Weekly bool
+}
+
+// Expired returns the reports that are not kept by the retention policy.
+func (rt *Retention) Expired(reports []*report.Report, now time.Time) []*report.Report {
+	refs := map[string][]*report.Report{}
+	for _, r := range reports {
+		k := fmt.Sprintf("%s@%s", r.Repository, r.Ref)
+		refs[k] = append(refs[k], r)
+	}
+	expired := []*report.Report{}
+	for _, rs := range refs {
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].Timestamp.After(rs[j].Timestamp) })
+		weeks := map[string]struct{}{}
+		for i, r := range rs {
+			y, w := r.Timestamp.UTC().ISOWeek()
+			week := fmt.Sprintf("%d-%d", y, w)
+			_, seen := weeks[week]
+			weeks[week] = struct{}{}
+			switch {
+			case i == 0 || i < rt.Last:
+			case rt.Within > 0 && now.Sub(r.Timestamp) <= rt.Within:
+			case rt.Weekly && !seen:
+			default:
+				expired = append(expired, r)
+			}
+		}
+	}
+	sort.SliceStable(expired, func(i, j int) bool { return expired[i].Timestamp.Before(expired[j].Timestamp) })
+	return expired
+}
+
+// Prune deletes the reports of the repository kept as history that are expired by the retention policy,
+// and returns the paths ( or the ids of the rows for BigQuery ) of them. If dryRun is true, it deletes nothing.
+func Prune(ctx context.Context, d Datastore, repository string, rt *Retention, dryRun bool) ([]string, error) {
+	if b, ok := d.(*bq.BQ); ok {
+		return pruneBQ(ctx, b, repository, rt, dryRun)
+	}
+	fsys, err := d.FS()
+	if err != nil {
+		return nil, err
+	}
+	paths := map[*report.Report]string{}
+	reports := []*report.Report{}
+	if err := fs.WalkDir(fsys, path.Join(repository, "history"), func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() || !strings.HasSuffix(p, ".json") {
+			return nil
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		r := &report.Report{}
+		if err := json.Unmarshal(b, r); err != nil || r.Timestamp.IsZero() {
+			// not a report
+			return nil
+		}
+		paths[r] = p
+		reports = append(reports, r)
+		return nil
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	expired := []string{}
+	for _, r := range rt.Expired(reports, time.Now()) {
+		expired = append(expired, paths[r])
+	}
+	if dryRun || len(expired) == 0 {
+		return expired, nil
+	}
+	if err := d.Delete(ctx, expired); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// bqStreamingBufferWindow is the duration during which the rows streamed into BigQuery may still be in the streaming buffer and cannot be deleted.
+const bqStreamingBufferWindow = 90 * time.Minute
+
+// pruneBQ deletes the expired rows of the reports from the BigQuery table with DELETE statement ( BQ.Delete is not supported ).
+func pruneBQ(ctx context.Context, b *bq.BQ, repository string, rt *Retention, dryRun bool) ([]string, error) {
+	repo, err := gh.Parse(repository)
+	if err != nil {
+		return nil, err
+	}
+	records, err := b.ListReports(ctx, repo.Owner, repo.Reponame())
+	if err != nil {
+		return nil, err
+	}
+	expired := expiredRecordIDs(records, repository, rt, time.Now())
+	if dryRun || len(expired) == 0 {
+		return expired, nil
+	}
+	if err := b.DeleteReports(ctx, expired); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// expiredRecordIDs returns the ids of the rows expired by the retention policy.
+// The rows that may still be in the streaming buffer are not expired because they cannot be deleted yet.
+func expiredRecordIDs(records []*bq.ReportRecord, repository string, rt *Retention, now time.Time) []string {
+	ids := map[*report.Report]string{}
+	reports := []*report.Report{}
+	for _, rr := range records {
+		r := &report.Report{
+			Repository: repository,
+			Ref:        rr.Ref,
+			Commit:     rr.Commit,
+			Timestamp:  rr.Timestamp,
+		}
+		ids[r] = rr.Id
+		reports = append(reports, r)
+	}
+	expired := []string{}
+	for _, r := range rt.Expired(reports, now) {
+		if now.Sub(r.Timestamp) < bqStreamingBufferWindow {
+			continue
+		}
+		expired = append(expired, ids[r])
+	}
+	return expired
+}
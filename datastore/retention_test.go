@@ -0,0 +1,155 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/datastore/bq"
+	"github.com/k1LoW/octocov/datastore/local"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	reports := []*report.Report{}
+	// a report every day for 28 days ( 2022-03-03 ... 2022-03-30, Thursday ... Wednesday )
+	for i := 1; i <= 28; i++ {
+		reports = append(reports, &report.Report{
+			Repository: "owner/repo",
+			Ref:        "refs/heads/main",
+			Commit:     fmt.Sprintf("main-%d", i),
+			Timestamp:  now.Add(-time.Duration(i) * 24 * time.Hour),
+		})
+	}
+	reports = append(reports, &report.Report{
+		Repository: "owner/repo",
+		Ref:        "refs/heads/feature",
+		Commit:     "feature-1",
+		Timestamp:  now.Add(-100 * 24 * time.Hour),
+	})
+
+	tests := []struct {
+		rt          *Retention
+		wantExpired int
+		wantKept    []string
+	}{
+		{&Retention{Last: 3}, 25, []string{"main-1", "main-2", "main-3", "feature-1"}},
+		{&Retention{Within: 7 * 24 * time.Hour}, 21, []string{"main-1", "main-7", "feature-1"}},
+		{&Retention{Within: 7 * 24 * time.Hour, Weekly: true}, 18, []string{"main-1", "main-7", "main-11", "main-18", "main-25", "feature-1"}},
+		{&Retention{Last: 100}, 0, []string{"main-28", "feature-1"}},
+	}
+	for _, tt := range tests {
+		expired := tt.rt.Expired(reports, now)
+		if got := len(expired); got != tt.wantExpired {
+			t.Errorf("got %v\nwant %v", got, tt.wantExpired)
+		}
+		for _, r := range expired {
+			for _, k := range tt.wantKept {
+				if r.Commit == k {
+					t.Errorf("%s should be kept", k)
+				}
+			}
+		}
+	}
+}
+
+func TestExpiredRecordIDs(t *testing.T) {
+	now := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	records := []*bq.ReportRecord{
+		{Id: "id0", Ref: "refs/heads/main", Commit: "commit0", Timestamp: now.Add(-10 * time.Minute)},
+		{Id: "id1", Ref: "refs/heads/main", Commit: "commit1", Timestamp: now.Add(-30 * time.Minute)},
+		{Id: "id2", Ref: "refs/heads/main", Commit: "commit2", Timestamp: now.Add(-3 * time.Hour)},
+		{Id: "id3", Ref: "refs/heads/main", Commit: "commit3", Timestamp: now.Add(-48 * time.Hour)},
+	}
+	rt := &Retention{Last: 1}
+	got := expiredRecordIDs(records, "owner/repo", rt, now)
+	// id1 is expired but may still be in the streaming buffer
+	if diff := cmp.Diff(got, []string{"id3", "id2"}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	d, err := local.New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		r := &report.Report{
+			Repository: "owner/repo",
+			Ref:        "refs/heads/main",
+			Commit:     fmt.Sprintf("commit%d", i),
+			Timestamp:  now.Add(-time.Duration(i) * time.Hour).UTC(),
+		}
+		if err := d.StoreReport(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+		if err := StoreReportHistory(ctx, d, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rt := &Retention{Last: 2}
+
+	tests := []struct {
+		dryRun   bool
+		want     int
+		wantLeft int
+	}{
+		{true, 3, 5},
+		{false, 3, 2},
+		{false, 0, 2},
+	}
+	for _, tt := range tests {
+		got, err := Prune(ctx, d, "owner/repo", rt, tt.dryRun)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("got %v\nwant %v", len(got), tt.want)
+		}
+		entries, err := os.ReadDir(filepath.Join(root, "owner", "repo", "history"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != tt.wantLeft {
+			t.Errorf("got %v\nwant %v", len(entries), tt.wantLeft)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "owner", "repo", "report.json")); err != nil {
+		t.Error(err)
+	}
+	// the last 2 reports should be kept
+	fsys, err := d.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := ReadReports(fsys, "owner/repo/history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := []string{}
+	for _, r := range left {
+		commits = append(commits, r.Commit)
+	}
+	sort.Strings(commits)
+	if diff := cmp.Diff(commits, []string{"commit0", "commit1"}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	got, err := Prune(ctx, d, "owner/other", rt, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, []string{}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
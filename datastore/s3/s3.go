@@ -47,6 +47,34 @@ func (s *S3) Put(ctx context.Context, path string, content []byte) error {
 	return nil
 }
 
+func (s *S3) Delete(ctx context.Context, paths []string) error {
+	// DeleteObjects deletes up to 1000 objects per request
+	for i := 0; i < len(paths); i += 1000 {
+		end := i + 1000
+		if end > len(paths) {
+			end = len(paths)
+		}
+		objects := []*s3.ObjectIdentifier{}
+		for _, path := range paths[i:end] {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(filepath.Join(s.prefix, path))})
+		}
+		out, err := s.client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: &s.bucket,
+			Delete: &s3.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("failed to delete %s: %s", aws.StringValue(out.Errors[0].Key), aws.StringValue(out.Errors[0].Message))
+		}
+	}
+	return nil
+}
+
 func (s *S3) FS() (fs.FS, error) {
 	return fs.Sub(s3fs.New(s.client, s.bucket), s.prefix)
 }
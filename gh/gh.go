@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -118,6 +120,57 @@ func (g *Gh) PushContent(ctx context.Context, owner, repo, branch, content, cp,
 	return nil
 }
 
+// DeleteContents deletes the files in a commit.
+func (g *Gh) DeleteContents(ctx context.Context, owner, repo, branch string, paths []string, message string) error {
+	srv := g.client.Git
+	dRef, _, err := srv.GetRef(ctx, owner, repo, path.Join("heads", branch))
+	if err != nil {
+		return err
+	}
+
+	parent, _, err := srv.GetCommit(ctx, owner, repo, *dRef.Object.SHA)
+	if err != nil {
+		return err
+	}
+
+	entries := []*github.TreeEntry{}
+	for _, p := range paths {
+		// a tree entry without SHA and content deletes the file
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(p),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+		})
+	}
+	tree, _, err := srv.CreateTree(ctx, owner, repo, *dRef.Object.SHA, entries)
+	if err != nil {
+		return err
+	}
+
+	commit := &github.Commit{
+		Message: github.String(message),
+		Tree:    tree,
+		Parents: []*github.Commit{parent},
+	}
+	resC, _, err := srv.CreateCommit(ctx, owner, repo, commit)
+	if err != nil {
+		return err
+	}
+
+	nref := &github.Reference{
+		Ref: github.String(path.Join("refs", "heads", branch)),
+		Object: &github.GitObject{
+			Type: github.String("commit"),
+			SHA:  resC.SHA,
+		},
+	}
+	if _, _, err := srv.UpdateRef(ctx, owner, repo, nref, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (g *Gh) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
 	r, _, err := g.client.Repositories.Get(ctx, owner, repo)
 	if err != nil {
@@ -126,6 +179,14 @@ func (g *Gh) GetDefaultBranch(ctx context.Context, owner, repo string) (string,
 	return r.GetDefaultBranch(), nil
 }
 
+func (g *Gh) ListTopics(ctx context.Context, owner, repo string) ([]string, error) {
+	topics, _, err := g.client.Repositories.ListAllTopics(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
 func (g *Gh) GetRawRootURL(ctx context.Context, owner, repo string) (string, error) {
 	b, err := g.GetDefaultBranch(ctx, owner, repo)
 	if err != nil {
@@ -249,6 +310,33 @@ func (g *Gh) DetectCurrentPullRequestNumber(ctx context.Context, owner, repo str
 type PullRequestFile struct {
 	Filename string
 	BlobURL  string
+	Patch    string
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// AddedLines returns the line numbers of the lines added by the pull request.
+func (f *PullRequestFile) AddedLines() []int {
+	lines := []int{}
+	n := 0
+	for _, l := range strings.Split(f.Patch, "\n") {
+		if m := hunkHeaderRe.FindStringSubmatch(l); m != nil {
+			n, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(l, "+"):
+			lines = append(lines, n)
+			n += 1
+		case strings.HasPrefix(l, "-"), strings.HasPrefix(l, "\\"):
+		default:
+			n += 1
+		}
+	}
+	return lines
 }
 
 func (g *Gh) GetPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]*PullRequestFile, error) {
@@ -269,6 +357,7 @@ func (g *Gh) GetPullRequestFiles(ctx context.Context, owner, repo string, number
 			files = append(files, &PullRequestFile{
 				Filename: f.GetFilename(),
 				BlobURL:  f.GetBlobURL(),
+				Patch:    f.GetPatch(),
 			})
 		}
 		page += 1
@@ -403,30 +492,291 @@ L:
 
 const commentSig = "<!-- octocov -->"
 
-func (g *Gh) PutComment(ctx context.Context, owner, repo string, n int, comment string) error {
-	if err := g.deleteCurrentIssueComment(ctx, owner, repo, n); err != nil {
+const (
+	// CommentModeUpdate edits the previous report comment in place.
+	CommentModeUpdate = "update"
+	// CommentModeRecreate deletes the previous report comments and posts a new one.
+	CommentModeRecreate = "recreate"
+	// CommentModeAppend posts a new report comment and leaves the previous ones as they are.
+	CommentModeAppend = "append"
+	// CommentModeHidePrevious minimizes the previous report comments and posts a new one.
+	CommentModeHidePrevious = "hide-previous"
+)
+
+var CommentModes = []string{CommentModeUpdate, CommentModeRecreate, CommentModeAppend, CommentModeHidePrevious}
+
+const (
+	mergedCommentSig       = "<!-- octocov-merged -->"
+	commentSectionStartFmt = "<!-- octocov-section:%s -->"
+	commentSectionEndFmt   = "<!-- octocov-section-end:%s -->"
+)
+
+type CommentOptions struct {
+	// Mode is how to handle the comment posted by the previous run (see CommentModes).
+	Mode string
+	// Key namespaces the comment so that each run owns its own comment.
+	Key string
+	// Merge puts the comment into a keyed section of a single combined comment.
+	Merge bool
+}
+
+func (g *Gh) PutComment(ctx context.Context, owner, repo string, n int, comment string, opts *CommentOptions) error {
+	sig := commentSigWithKey(opts.Key)
+	if opts.Merge {
+		sig = mergedCommentSig
+	}
+	current, err := g.listCurrentIssueComments(ctx, owner, repo, n, sig)
+	if err != nil {
 		return err
 	}
-	c := strings.Join([]string{comment, commentSig}, "\n")
+	if opts.Merge {
+		sections := commentSections{}
+		if len(current) > 0 {
+			sections = parseCommentSections(current[len(current)-1].GetBody())
+		}
+		sections[opts.Key] = comment
+		comment = sections.String()
+	}
+	c := strings.Join([]string{comment, sig}, "\n")
+	switch opts.Mode {
+	case CommentModeUpdate:
+		if len(current) > 0 {
+			latest := current[len(current)-1]
+			if _, _, err := g.client.Issues.EditComment(ctx, owner, repo, latest.GetID(), &github.IssueComment{Body: &c}); err != nil {
+				return err
+			}
+			return nil
+		}
+	case CommentModeRecreate, "":
+		for _, cc := range current {
+			if _, err := g.client.Issues.DeleteComment(ctx, owner, repo, cc.GetID()); err != nil {
+				return err
+			}
+		}
+	case CommentModeAppend:
+	case CommentModeHidePrevious:
+		ids := []string{}
+		for _, cc := range current {
+			ids = append(ids, cc.GetNodeID())
+		}
+		minimized, err := g.minimizedComments(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, cc := range current {
+			if minimized[cc.GetNodeID()] {
+				continue
+			}
+			if err := g.minimizeComment(ctx, cc.GetNodeID()); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("invalid comment mode: %s", opts.Mode)
+	}
 	if _, _, err := g.client.Issues.CreateComment(ctx, owner, repo, n, &github.IssueComment{Body: &c}); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *Gh) deleteCurrentIssueComment(ctx context.Context, owner, repo string, n int) error {
-	opts := &github.IssueListCommentsOptions{}
-	comments, _, err := g.client.Issues.ListComments(ctx, owner, repo, n, opts)
-	if err != nil {
-		return err
+func (g *Gh) listCurrentIssueComments(ctx context.Context, owner, repo string, n int, sig string) ([]*github.IssueComment, error) {
+	current := []*github.IssueComment{}
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
 	}
-	for _, c := range comments {
-		if strings.Contains(*c.Body, commentSig) {
-			_, err = g.client.Issues.DeleteComment(ctx, owner, repo, *c.ID)
-			if err != nil {
-				return err
+	for {
+		comments, res, err := g.client.Issues.ListComments(ctx, owner, repo, n, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			if strings.Contains(c.GetBody(), sig) {
+				current = append(current, c)
 			}
 		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return current, nil
+}
+
+// PutCommitComment posts the comment to the commit, or edits the comment posted to the commit by the previous run.
+func (g *Gh) PutCommitComment(ctx context.Context, owner, repo, sha, comment, key string) error {
+	sig := commentSigWithKey(key)
+	c := strings.Join([]string{comment, sig}, "\n")
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		comments, res, err := g.client.Repositories.ListCommitComments(ctx, owner, repo, sha, opts)
+		if err != nil {
+			return err
+		}
+		for _, cc := range comments {
+			if strings.Contains(cc.GetBody(), sig) {
+				if _, _, err := g.client.Repositories.UpdateComment(ctx, owner, repo, cc.GetID(), &github.RepositoryComment{Body: &c}); err != nil {
+					return err
+				}
+				return nil
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	if _, _, err := g.client.Repositories.CreateComment(ctx, owner, repo, sha, &github.RepositoryComment{Body: &c}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func commentSigWithKey(key string) string {
+	if key == "" {
+		return commentSig
+	}
+	return fmt.Sprintf("<!-- octocov:%s -->", key)
+}
+
+// commentSections is the keyed sections of a combined comment.
+type commentSections map[string]string
+
+func parseCommentSections(body string) commentSections {
+	sections := commentSections{}
+	for {
+		i := strings.Index(body, "<!-- octocov-section:")
+		if i < 0 {
+			break
+		}
+		body = body[i:]
+		j := strings.Index(body, " -->")
+		if j < 0 {
+			break
+		}
+		key := strings.TrimPrefix(body[:j], "<!-- octocov-section:")
+		start := fmt.Sprintf(commentSectionStartFmt, key)
+		end := fmt.Sprintf(commentSectionEndFmt, key)
+		k := strings.Index(body, end)
+		if k < 0 {
+			break
+		}
+		sections[key] = strings.Trim(body[len(start):k], "\n")
+		body = body[k+len(end):]
+	}
+	return sections
+}
+
+func (s commentSections) String() string {
+	keys := []string{}
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := []string{}
+	for _, k := range keys {
+		out = append(out, strings.Join([]string{fmt.Sprintf(commentSectionStartFmt, k), s[k], fmt.Sprintf(commentSectionEndFmt, k)}, "\n"))
+	}
+	return strings.Join(out, "\n\n")
+}
+
+const minimizeCommentMutation = `mutation($id: ID!) {
+  minimizeComment(input: {subjectId: $id, classifier: OUTDATED}) {
+    minimizedComment {
+      isMinimized
+    }
+  }
+}`
+
+func (g *Gh) minimizeComment(ctx context.Context, nodeID string) error {
+	q := map[string]interface{}{
+		"query": minimizeCommentMutation,
+		"variables": map[string]interface{}{
+			"id": nodeID,
+		},
+	}
+	res := struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	if err := g.graphql(ctx, q, &res); err != nil {
+		return err
+	}
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("failed to minimize comment (%s): %s", nodeID, res.Errors[0].Message)
+	}
+	return nil
+}
+
+const minimizedCommentsQuery = `query($ids: [ID!]!) {
+  nodes(ids: $ids) {
+    ... on IssueComment {
+      id
+      isMinimized
+    }
+  }
+}`
+
+// minimizedCommentsMaxNodes is the maximum number of node IDs that can be passed to `nodes`.
+const minimizedCommentsMaxNodes = 100
+
+// minimizedComments returns whether each comment of nodeIDs is already minimized.
+func (g *Gh) minimizedComments(ctx context.Context, nodeIDs []string) (map[string]bool, error) {
+	minimized := map[string]bool{}
+	for i := 0; i < len(nodeIDs); i += minimizedCommentsMaxNodes {
+		end := i + minimizedCommentsMaxNodes
+		if end > len(nodeIDs) {
+			end = len(nodeIDs)
+		}
+		q := map[string]interface{}{
+			"query": minimizedCommentsQuery,
+			"variables": map[string]interface{}{
+				"ids": nodeIDs[i:end],
+			},
+		}
+		res := struct {
+			Data struct {
+				Nodes []struct {
+					ID          string `json:"id"`
+					IsMinimized bool   `json:"isMinimized"`
+				} `json:"nodes"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}{}
+		if err := g.graphql(ctx, q, &res); err != nil {
+			return nil, err
+		}
+		if len(res.Errors) > 0 {
+			return nil, fmt.Errorf("failed to get comments: %s", res.Errors[0].Message)
+		}
+		for _, n := range res.Data.Nodes {
+			minimized[n.ID] = n.IsMinimized
+		}
+	}
+	return minimized, nil
+}
+
+func (g *Gh) graphql(ctx context.Context, q, v interface{}) error {
+	u := *g.client.BaseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		// GitHub Enterprise Server
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path = path.Join(u.Path, "graphql")
+	}
+	req, err := g.client.NewRequest(http.MethodPost, u.String(), q)
+	if err != nil {
+		return err
+	}
+	if _, err := g.client.Do(ctx, req, v); err != nil {
+		return err
 	}
 	return nil
 }
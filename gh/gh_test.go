@@ -1,9 +1,18 @@
 package gh
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
 )
 
 func TestParse(t *testing.T) {
@@ -36,3 +45,212 @@ func TestParse(t *testing.T) {
 		}
 	}
 }
+
+func TestPutComment(t *testing.T) {
+	tests := []struct {
+		mode         string
+		wantCreated  int
+		wantEdited   int
+		wantDeleted  int
+		wantMinimize int
+	}{
+		{CommentModeRecreate, 1, 0, 2, 0},
+		{CommentModeUpdate, 0, 1, 0, 0},
+		{CommentModeAppend, 1, 0, 0, 0},
+		{CommentModeHidePrevious, 1, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		var created, edited, deleted, minimized int
+		var editedID int64
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+				[]*github.IssueComment{
+					{ID: github.Int64(1), NodeID: github.String("IC_1"), Body: github.String("report\n<!-- octocov -->")},
+					{ID: github.Int64(2), NodeID: github.String("IC_2"), Body: github.String("LGTM")},
+					{ID: github.Int64(3), NodeID: github.String("IC_3"), Body: github.String("report\n<!-- octocov -->")},
+				},
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposIssuesCommentsByOwnerByRepoByIssueNumber,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					created += 1
+					_, _ = w.Write(mock.MustMarshal(github.IssueComment{}))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposIssuesCommentsByOwnerByRepoByCommentId,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					edited += 1
+					editedID, _ = strconv.ParseInt(path.Base(r.URL.Path), 10, 64)
+					_, _ = w.Write(mock.MustMarshal(github.IssueComment{}))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposIssuesCommentsByOwnerByRepoByCommentId,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					deleted += 1
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.EndpointPattern{Pattern: "/graphql", Method: "POST"},
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					b, _ := io.ReadAll(r.Body)
+					if strings.Contains(string(b), "isMinimized") && !strings.Contains(string(b), "mutation") {
+						// IC_1 is already minimized
+						_, _ = w.Write([]byte(`{"data":{"nodes":[{"id":"IC_1","isMinimized":true},{"id":"IC_3","isMinimized":false}]}}`))
+						return
+					}
+					minimized += 1
+					_, _ = w.Write([]byte(`{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true}}}}`))
+				}),
+			),
+		)
+		g := &Gh{client: github.NewClient(mockedHTTPClient)}
+		if err := g.PutComment(context.Background(), "owner", "repo", 1, "report", &CommentOptions{Mode: tt.mode}); err != nil {
+			t.Fatal(err)
+		}
+		if created != tt.wantCreated {
+			t.Errorf("%s: created got %v\nwant %v", tt.mode, created, tt.wantCreated)
+		}
+		if edited != tt.wantEdited {
+			t.Errorf("%s: edited got %v\nwant %v", tt.mode, edited, tt.wantEdited)
+		}
+		if tt.wantEdited > 0 && editedID != 3 {
+			t.Errorf("%s: edited comment got %v\nwant %v", tt.mode, editedID, 3)
+		}
+		if deleted != tt.wantDeleted {
+			t.Errorf("%s: deleted got %v\nwant %v", tt.mode, deleted, tt.wantDeleted)
+		}
+		if minimized != tt.wantMinimize {
+			t.Errorf("%s: minimized got %v\nwant %v", tt.mode, minimized, tt.wantMinimize)
+		}
+	}
+}
+
+func TestPutCommentWithKey(t *testing.T) {
+	tests := []struct {
+		key         string
+		merge       bool
+		wantDeleted int
+		wantBody    string
+	}{
+		{"", false, 1, "report\n<!-- octocov -->"},
+		{"frontend", false, 1, "report\n<!-- octocov:frontend -->"},
+		{"backend", false, 0, "report\n<!-- octocov:backend -->"},
+		{"frontend", true, 1, "<!-- octocov-section:backend -->\nbackend report\n<!-- octocov-section-end:backend -->\n\n<!-- octocov-section:frontend -->\nreport\n<!-- octocov-section-end:frontend -->\n<!-- octocov-merged -->"},
+	}
+	for _, tt := range tests {
+		var deleted int
+		var got string
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+				[]*github.IssueComment{
+					{ID: github.Int64(1), Body: github.String("old report\n<!-- octocov -->")},
+					{ID: github.Int64(2), Body: github.String("old report\n<!-- octocov:frontend -->")},
+					{ID: github.Int64(3), Body: github.String("<!-- octocov-section:backend -->\nbackend report\n<!-- octocov-section-end:backend -->\n\n<!-- octocov-section:frontend -->\nold report\n<!-- octocov-section-end:frontend -->\n<!-- octocov-merged -->")},
+				},
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposIssuesCommentsByOwnerByRepoByIssueNumber,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					c := &github.IssueComment{}
+					if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+						t.Fatal(err)
+					}
+					got = c.GetBody()
+					_, _ = w.Write(mock.MustMarshal(c))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposIssuesCommentsByOwnerByRepoByCommentId,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					deleted += 1
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		)
+		g := &Gh{client: github.NewClient(mockedHTTPClient)}
+		if err := g.PutComment(context.Background(), "owner", "repo", 1, "report", &CommentOptions{Mode: CommentModeRecreate, Key: tt.key, Merge: tt.merge}); err != nil {
+			t.Fatal(err)
+		}
+		if deleted != tt.wantDeleted {
+			t.Errorf("key %s: deleted got %v\nwant %v", tt.key, deleted, tt.wantDeleted)
+		}
+		if got != tt.wantBody {
+			t.Errorf("key %s: got %v\nwant %v", tt.key, got, tt.wantBody)
+		}
+	}
+}
+
+func TestAddedLines(t *testing.T) {
+	tests := []struct {
+		patch string
+		want  []int
+	}{
+		{"", []int{}},
+		{"@@ -0,0 +1,2 @@\n+a\n+b", []int{1, 2}},
+		{"@@ -3,4 +3,5 @@ func main() {\n a\n-b\n+c\n+d\n e\n\\ No newline at end of file\n@@ -20 +21,2 @@\n x\n+y", []int{4, 5, 22}},
+	}
+	for _, tt := range tests {
+		got := (&PullRequestFile{Patch: tt.patch}).AddedLines()
+		if len(got) != len(tt.want) {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestPutCommitComment(t *testing.T) {
+	tests := []struct {
+		key         string
+		wantCreated int
+		wantUpdated int
+	}{
+		{"", 0, 1},
+		{"frontend", 1, 0},
+	}
+	for _, tt := range tests {
+		var created, updated int
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposCommitsCommentsByOwnerByRepoByCommitSha,
+				[]*github.RepositoryComment{
+					{ID: github.Int64(1), Body: github.String("nice")},
+					{ID: github.Int64(2), Body: github.String("report\n<!-- octocov -->")},
+				},
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposCommitsCommentsByOwnerByRepoByCommitSha,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					created += 1
+					_, _ = w.Write(mock.MustMarshal(github.RepositoryComment{}))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposCommentsByOwnerByRepoByCommentId,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					updated += 1
+					_, _ = w.Write(mock.MustMarshal(github.RepositoryComment{}))
+				}),
+			),
+		)
+		g := &Gh{client: github.NewClient(mockedHTTPClient)}
+		if err := g.PutCommitComment(context.Background(), "owner", "repo", "1234567", "report", tt.key); err != nil {
+			t.Fatal(err)
+		}
+		if created != tt.wantCreated {
+			t.Errorf("created got %v\nwant %v", created, tt.wantCreated)
+		}
+		if updated != tt.wantUpdated {
+			t.Errorf("updated got %v\nwant %v", updated, tt.wantUpdated)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+const alertIssueSig = "<!-- octocov-alert -->"
+
+// PutAlertIssue opens the alert issue with the labels, or updates the body of the alert issue opened by the previous run.
+func (g *Gh) PutAlertIssue(ctx context.Context, owner, repo, title, body string, labels []string, key string) (int, error) {
+	sig := alertIssueSigWithKey(key)
+	b := strings.Join([]string{body, sig}, "\n")
+	issues, err := g.listOpenAlertIssues(ctx, owner, repo, labels, sig)
+	if err != nil {
+		return 0, err
+	}
+	if len(issues) > 0 {
+		i := issues[0]
+		if _, _, err := g.client.Issues.Edit(ctx, owner, repo, i.GetNumber(), &github.IssueRequest{Title: &title, Body: &b}); err != nil {
+			return 0, err
+		}
+		return i.GetNumber(), nil
+	}
+	i, _, err := g.client.Issues.Create(ctx, owner, repo, &github.IssueRequest{
+		Title:  &title,
+		Body:   &b,
+		Labels: &labels,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return i.GetNumber(), nil
+}
+
+// CloseAlertIssues comments on and closes the alert issues opened by the previous runs.
+func (g *Gh) CloseAlertIssues(ctx context.Context, owner, repo, comment string, labels []string, key string) ([]int, error) {
+	sig := alertIssueSigWithKey(key)
+	issues, err := g.listOpenAlertIssues(ctx, owner, repo, labels, sig)
+	if err != nil {
+		return nil, err
+	}
+	closed := []int{}
+	state := "closed"
+	for _, i := range issues {
+		if _, _, err := g.client.Issues.CreateComment(ctx, owner, repo, i.GetNumber(), &github.IssueComment{Body: &comment}); err != nil {
+			return closed, err
+		}
+		if _, _, err := g.client.Issues.Edit(ctx, owner, repo, i.GetNumber(), &github.IssueRequest{State: &state}); err != nil {
+			return closed, err
+		}
+		closed = append(closed, i.GetNumber())
+	}
+	return closed, nil
+}
+
+func (g *Gh) listOpenAlertIssues(ctx context.Context, owner, repo string, labels []string, sig string) ([]*github.Issue, error) {
+	issues := []*github.Issue{}
+	opts := &github.IssueListByRepoOptions{
+		State:  "open",
+		Labels: labels,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		is, res, err := g.client.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range is {
+			if i.IsPullRequest() {
+				continue
+			}
+			if !strings.Contains(i.GetBody(), sig) {
+				continue
+			}
+			issues = append(issues, i)
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return issues, nil
+}
+
+func alertIssueSigWithKey(key string) string {
+	if key == "" {
+		return alertIssueSig
+	}
+	return fmt.Sprintf("<!-- octocov-alert:%s -->", key)
+}
+
+// UpdateLabels adds the labels to the issue ( or pull request ) and removes the labels from it.
+// Only the labels that need to be changed are requested.
+func (g *Gh) UpdateLabels(ctx context.Context, owner, repo string, n int, add, remove []string) error {
+	current := map[string]struct{}{}
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		labels, res, err := g.client.Issues.ListLabelsByIssue(ctx, owner, repo, n, opts)
+		if err != nil {
+			return err
+		}
+		for _, l := range labels {
+			current[l.GetName()] = struct{}{}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	adding := []string{}
+	for _, l := range add {
+		if _, ok := current[l]; !ok {
+			adding = append(adding, l)
+		}
+	}
+	if len(adding) > 0 {
+		if _, _, err := g.client.Issues.AddLabelsToIssue(ctx, owner, repo, n, adding); err != nil {
+			return err
+		}
+	}
+	for _, l := range remove {
+		if _, ok := current[l]; !ok {
+			continue
+		}
+		if _, err := g.client.Issues.RemoveLabelForIssue(ctx, owner, repo, n, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+)
+
+func mockedAlertIssues() []*github.Issue {
+	return []*github.Issue{
+		{Number: github.Int(1), Body: github.String("bug")},
+		{Number: github.Int(2), Body: github.String("report\n<!-- octocov-alert -->"), PullRequestLinks: &github.PullRequestLinks{}},
+		{Number: github.Int(3), Body: github.String("report\n<!-- octocov-alert -->")},
+	}
+}
+
+func TestPutAlertIssue(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    int
+		created bool
+	}{
+		{"", 3, false},
+		{"frontend", 10, true},
+	}
+	for _, tt := range tests {
+		var gotLabels []string
+		edited := false
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposIssuesByOwnerByRepo,
+				mockedAlertIssues(),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposIssuesByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					req := &github.IssueRequest{}
+					if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+						t.Fatal(err)
+					}
+					gotLabels = req.GetLabels()
+					_, _ = w.Write(mock.MustMarshal(github.Issue{Number: github.Int(10)}))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposIssuesByOwnerByRepoByIssueNumber,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					edited = true
+					_, _ = w.Write(mock.MustMarshal(github.Issue{}))
+				}),
+			),
+		)
+		g := &Gh{client: github.NewClient(mockedHTTPClient)}
+		got, err := g.PutAlertIssue(context.Background(), "owner", "repo", "title", "report", []string{"octocov-alert"}, tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if edited == tt.created {
+			t.Errorf("edited got %v\nwant %v", edited, !tt.created)
+		}
+		if tt.created && (len(gotLabels) != 1 || gotLabels[0] != "octocov-alert") {
+			t.Errorf("got %v\nwant %v", gotLabels, []string{"octocov-alert"})
+		}
+	}
+}
+
+func TestCloseAlertIssues(t *testing.T) {
+	commented := 0
+	var gotState string
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposIssuesByOwnerByRepo,
+			mockedAlertIssues(),
+		),
+		mock.WithRequestMatchHandler(
+			mock.PostReposIssuesCommentsByOwnerByRepoByIssueNumber,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				commented += 1
+				_, _ = w.Write(mock.MustMarshal(github.IssueComment{}))
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.PatchReposIssuesByOwnerByRepoByIssueNumber,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req := &github.IssueRequest{}
+				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+					t.Fatal(err)
+				}
+				gotState = req.GetState()
+				_, _ = w.Write(mock.MustMarshal(github.Issue{}))
+			}),
+		),
+	)
+	g := &Gh{client: github.NewClient(mockedHTTPClient)}
+	got, err := g.CloseAlertIssues(context.Background(), "owner", "repo", "recovered", []string{"octocov-alert"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != 3 {
+		t.Errorf("got %v\nwant %v", got, []int{3})
+	}
+	if commented != 1 {
+		t.Errorf("got %v\nwant %v", commented, 1)
+	}
+	if gotState != "closed" {
+		t.Errorf("got %v\nwant %v", gotState, "closed")
+	}
+}
+
+func TestUpdateLabels(t *testing.T) {
+	var added []string
+	removed := []string{}
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposIssuesLabelsByOwnerByRepoByIssueNumber,
+			[]*github.Label{
+				{Name: github.String("coverage:down")},
+				{Name: github.String("bug")},
+			},
+		),
+		mock.WithRequestMatchHandler(
+			mock.PostReposIssuesLabelsByOwnerByRepoByIssueNumber,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&added); err != nil {
+					t.Fatal(err)
+				}
+				_, _ = w.Write(mock.MustMarshal([]*github.Label{}))
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposIssuesLabelsByOwnerByRepoByIssueNumberByName,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				removed = append(removed, r.URL.Path)
+				w.WriteHeader(http.StatusOK)
+			}),
+		),
+	)
+	g := &Gh{client: github.NewClient(mockedHTTPClient)}
+	if err := g.UpdateLabels(context.Background(), "owner", "repo", 1, []string{"coverage:down", "needs-tests"}, []string{"coverage:up", "bug"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0] != "needs-tests" {
+		t.Errorf("got %v\nwant %v", added, []string{"needs-tests"})
+	}
+	if want := []string{"/repos/owner/repo/issues/1/labels/bug"}; len(removed) != 1 || removed[0] != want[0] {
+		t.Errorf("got %v\nwant %v", removed, want)
+	}
+}
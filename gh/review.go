@@ -0,0 +1,241 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+const inlineCommentSig = "<!-- octocov-inline -->"
+
+type ReviewComment struct {
+	Path      string
+	StartLine int
+	Line      int
+	Body      string
+}
+
+func (c *ReviewComment) rangeKey() string {
+	return lineRangeKey(c.Path, c.StartLine, c.Line)
+}
+
+type reviewThread struct {
+	ID         string
+	IsResolved bool
+	IsOutdated bool
+	Path       string
+	StartLine  int
+	Line       int
+	Body       string
+}
+
+func (t *reviewThread) rangeKey() string {
+	return lineRangeKey(t.Path, t.StartLine, t.Line)
+}
+
+func lineRangeKey(path string, start, end int) string {
+	if start == 0 {
+		start = end
+	}
+	return fmt.Sprintf("%s:%d-%d", path, start, end)
+}
+
+func inlineCommentSigWithKey(key string) string {
+	if key == "" {
+		return inlineCommentSig
+	}
+	return fmt.Sprintf("<!-- octocov-inline:%s -->", key)
+}
+
+// PutReviewComments posts the line comments that have not been posted yet as a single pull request review,
+// and resolves the review threads of the previous line comments that are no longer reported.
+// The number of unresolved line comments is kept within limit (0 means unlimited).
+func (g *Gh) PutReviewComments(ctx context.Context, owner, repo string, n int, comments []*ReviewComment, key string, limit int) error {
+	sig := inlineCommentSigWithKey(key)
+	threads, err := g.listReviewThreads(ctx, owner, repo, n)
+	if err != nil {
+		return err
+	}
+	reported := map[string]bool{}
+	for _, c := range comments {
+		reported[c.rangeKey()] = true
+	}
+	posted := map[string]bool{}
+	for _, t := range threads {
+		if t.IsResolved || !strings.Contains(t.Body, sig) {
+			continue
+		}
+		k := t.rangeKey()
+		if !t.IsOutdated && reported[k] && !posted[k] {
+			posted[k] = true
+			continue
+		}
+		if err := g.resolveReviewThread(ctx, t.ID); err != nil {
+			return err
+		}
+	}
+
+	open := len(posted)
+	drafts := []*github.DraftReviewComment{}
+	for _, c := range comments {
+		if posted[c.rangeKey()] {
+			continue
+		}
+		if limit > 0 && open >= limit {
+			break
+		}
+		open += 1
+		d := &github.DraftReviewComment{
+			Path: github.String(c.Path),
+			Body: github.String(strings.Join([]string{c.Body, sig}, "\n")),
+			Side: github.String("RIGHT"),
+			Line: github.Int(c.Line),
+		}
+		if c.StartLine > 0 && c.StartLine < c.Line {
+			d.StartSide = github.String("RIGHT")
+			d.StartLine = github.Int(c.StartLine)
+		}
+		drafts = append(drafts, d)
+	}
+	if len(drafts) == 0 {
+		return nil
+	}
+	if _, _, err := g.client.PullRequests.CreateReview(ctx, owner, repo, n, &github.PullRequestReviewRequest{
+		Event:    github.String("COMMENT"),
+		Comments: drafts,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          startLine
+          line
+          comments(first: 1) {
+            nodes {
+              body
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func (g *Gh) listReviewThreads(ctx context.Context, owner, repo string, n int) ([]*reviewThread, error) {
+	threads := []*reviewThread{}
+	var cursor *string
+	for {
+		q := map[string]interface{}{
+			"query": reviewThreadsQuery,
+			"variables": map[string]interface{}{
+				"owner":  owner,
+				"repo":   repo,
+				"number": n,
+				"cursor": cursor,
+			},
+		}
+		res := struct {
+			Data struct {
+				Repository struct {
+					PullRequest struct {
+						ReviewThreads struct {
+							PageInfo struct {
+								HasNextPage bool   `json:"hasNextPage"`
+								EndCursor   string `json:"endCursor"`
+							} `json:"pageInfo"`
+							Nodes []struct {
+								ID         string `json:"id"`
+								IsResolved bool   `json:"isResolved"`
+								IsOutdated bool   `json:"isOutdated"`
+								Path       string `json:"path"`
+								StartLine  int    `json:"startLine"`
+								Line       int    `json:"line"`
+								Comments   struct {
+									Nodes []struct {
+										Body string `json:"body"`
+									} `json:"nodes"`
+								} `json:"comments"`
+							} `json:"nodes"`
+						} `json:"reviewThreads"`
+					} `json:"pullRequest"`
+				} `json:"repository"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}{}
+		if err := g.graphql(ctx, q, &res); err != nil {
+			return nil, err
+		}
+		if len(res.Errors) > 0 {
+			return nil, fmt.Errorf("failed to list review threads (#%d): %s", n, res.Errors[0].Message)
+		}
+		rt := res.Data.Repository.PullRequest.ReviewThreads
+		for _, t := range rt.Nodes {
+			body := ""
+			if len(t.Comments.Nodes) > 0 {
+				body = t.Comments.Nodes[0].Body
+			}
+			threads = append(threads, &reviewThread{
+				ID:         t.ID,
+				IsResolved: t.IsResolved,
+				IsOutdated: t.IsOutdated,
+				Path:       t.Path,
+				StartLine:  t.StartLine,
+				Line:       t.Line,
+				Body:       body,
+			})
+		}
+		if !rt.PageInfo.HasNextPage {
+			break
+		}
+		c := rt.PageInfo.EndCursor
+		cursor = &c
+	}
+	return threads, nil
+}
+
+const resolveReviewThreadMutation = `mutation($id: ID!) {
+  resolveReviewThread(input: {threadId: $id}) {
+    thread {
+      isResolved
+    }
+  }
+}`
+
+func (g *Gh) resolveReviewThread(ctx context.Context, threadID string) error {
+	q := map[string]interface{}{
+		"query": resolveReviewThreadMutation,
+		"variables": map[string]interface{}{
+			"id": threadID,
+		},
+	}
+	res := struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	if err := g.graphql(ctx, q, &res); err != nil {
+		return err
+	}
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("failed to resolve review thread (%s): %s", threadID, res.Errors[0].Message)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+)
+
+func TestPutReviewComments(t *testing.T) {
+	threads := `{"data":{"repository":{"pullRequest":{"reviewThreads":{"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[
+{"id":"T_1","isResolved":false,"isOutdated":false,"path":"main.go","startLine":10,"line":12,"comments":{"nodes":[{"body":"uncovered\n<!-- octocov-inline -->"}]}},
+{"id":"T_2","isResolved":false,"isOutdated":false,"path":"main.go","startLine":0,"line":20,"comments":{"nodes":[{"body":"uncovered\n<!-- octocov-inline -->"}]}},
+{"id":"T_3","isResolved":false,"isOutdated":false,"path":"main.go","startLine":0,"line":30,"comments":{"nodes":[{"body":"LGTM"}]}},
+{"id":"T_4","isResolved":true,"isOutdated":false,"path":"main.go","startLine":0,"line":40,"comments":{"nodes":[{"body":"uncovered\n<!-- octocov-inline -->"}]}}
+]}}}}}`
+	tests := []struct {
+		comments     []*ReviewComment
+		limit        int
+		wantResolved []string
+		wantPosted   []string
+	}{
+		{
+			[]*ReviewComment{
+				{Path: "main.go", StartLine: 10, Line: 12, Body: "uncovered"},
+				{Path: "main.go", StartLine: 40, Line: 40, Body: "uncovered"},
+				{Path: "main.go", StartLine: 50, Line: 52, Body: "uncovered"},
+			},
+			0,
+			[]string{"T_2"},
+			[]string{"main.go:40-40", "main.go:50-52"},
+		},
+		{
+			[]*ReviewComment{
+				{Path: "main.go", StartLine: 10, Line: 12, Body: "uncovered"},
+				{Path: "main.go", StartLine: 40, Line: 40, Body: "uncovered"},
+				{Path: "main.go", StartLine: 50, Line: 52, Body: "uncovered"},
+			},
+			2,
+			[]string{"T_2"},
+			[]string{"main.go:40-40"},
+		},
+		{
+			[]*ReviewComment{},
+			0,
+			[]string{"T_1", "T_2"},
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		resolved := []string{}
+		posted := []string{}
+		mockedHTTPClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.EndpointPattern{Pattern: "/graphql", Method: "POST"},
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					b, err := io.ReadAll(r.Body)
+					if err != nil {
+						t.Fatal(err)
+					}
+					q := struct {
+						Query     string                 `json:"query"`
+						Variables map[string]interface{} `json:"variables"`
+					}{}
+					if err := json.Unmarshal(b, &q); err != nil {
+						t.Fatal(err)
+					}
+					if strings.Contains(q.Query, "resolveReviewThread") {
+						resolved = append(resolved, q.Variables["id"].(string))
+						_, _ = w.Write([]byte(`{"data":{"resolveReviewThread":{"thread":{"isResolved":true}}}}`))
+						return
+					}
+					_, _ = w.Write([]byte(threads))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposPullsReviewsByOwnerByRepoByPullNumber,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					req := &github.PullRequestReviewRequest{}
+					if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+						t.Fatal(err)
+					}
+					for _, c := range req.Comments {
+						posted = append(posted, lineRangeKey(c.GetPath(), c.GetStartLine(), c.GetLine()))
+						if !strings.HasSuffix(c.GetBody(), inlineCommentSig) {
+							t.Errorf("got %v\nwant suffix %v", c.GetBody(), inlineCommentSig)
+						}
+					}
+					_, _ = w.Write(mock.MustMarshal(github.PullRequestReview{}))
+				}),
+			),
+		)
+		g := &Gh{client: github.NewClient(mockedHTTPClient)}
+		if err := g.PutReviewComments(context.Background(), "owner", "repo", 1, tt.comments, "", tt.limit); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(resolved, ",") != strings.Join(tt.wantResolved, ",") {
+			t.Errorf("resolved got %v\nwant %v", resolved, tt.wantResolved)
+		}
+		if strings.Join(posted, ",") != strings.Join(tt.wantPosted, ",") {
+			t.Errorf("posted got %v\nwant %v", posted, tt.wantPosted)
+		}
+	}
+}
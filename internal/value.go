@@ -4,6 +4,16 @@ func Bool(e bool) *bool {
 	return &e
 }
 
+// Contains returns true if s contains e.
+func Contains(s []string, e string) bool {
+	for _, v := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
 func IsEnable(e *bool) bool {
 	if e == nil {
 		return true
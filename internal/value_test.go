@@ -20,6 +20,24 @@ func TestBool(t *testing.T) {
 	}
 }
 
+func TestContains(t *testing.T) {
+	tests := []struct {
+		s    []string
+		e    string
+		want bool
+	}{
+		{[]string{"a", "b"}, "b", true},
+		{[]string{"a", "b"}, "c", false},
+		{nil, "a", false},
+	}
+	for _, tt := range tests {
+		got := Contains(tt.s, tt.e)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
 func TestIsEnable(t *testing.T) {
 	tests := []struct {
 		in   *bool
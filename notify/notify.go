@@ -0,0 +1,187 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+	"github.com/lestrrat-go/backoff/v2"
+)
+
+// defaultBody sends the summary metrics only, not the full report ( e.g. the coverage of each file ).
+const defaultBody = `{{ json .Summary }}`
+
+// Summary is the summary metrics of the report passed to the body template as `.Summary`.
+type Summary struct {
+	Repository        string       `json:"repository"`
+	Ref               string       `json:"ref"`
+	Commit            string       `json:"commit"`
+	Coverage          *float64     `json:"coverage"`
+	CodeToTestRatio   *float64     `json:"code_to_test_ratio"`
+	TestExecutionTime *float64     `json:"test_execution_time"`
+	Timestamp         time.Time    `json:"timestamp"`
+	Diff              *SummaryDiff `json:"diff"`
+}
+
+// SummaryDiff is the differences of the metrics from the previous report ( nil if not measured in both reports ).
+type SummaryDiff struct {
+	Coverage          *float64 `json:"coverage"`
+	CodeToTestRatio   *float64 `json:"code_to_test_ratio"`
+	TestExecutionTime *float64 `json:"test_execution_time"`
+}
+
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+	Body    string
+	client  *http.Client
+	policy  backoff.Policy
+}
+
+func NewWebhook(u string, headers map[string]string, body string) *Webhook {
+	if body == "" {
+		body = defaultBody
+	}
+	return &Webhook{
+		URL:     u,
+		Headers: headers,
+		Body:    body,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		policy: backoff.Exponential(
+			backoff.WithMinInterval(time.Second),
+			backoff.WithMaxInterval(30*time.Second),
+			backoff.WithJitterFactor(0.05),
+			backoff.WithMaxRetries(5),
+		),
+	}
+}
+
+func (w *Webhook) SetClient(client *http.Client) {
+	w.client = client
+}
+
+// Send renders the body template with the report and the diff report (d may be nil) and posts it to the webhook URL.
+// Requests are retried with backoff on connection errors, 429 and 5xx responses.
+func (w *Webhook) Send(ctx context.Context, r *report.Report, d *report.DiffReport) error {
+	body, err := w.render(r, d)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	b := w.policy.Start(ctx)
+	for backoff.Continue(b) {
+		retryable, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable {
+			return err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ctx.Err()
+	}
+	return fmt.Errorf("failed to send webhook (%s): %w", w.URL, lastErr)
+}
+
+func (w *Webhook) render(r *report.Report, d *report.DiffReport) ([]byte, error) {
+	tmpl, err := template.New("body").Funcs(funcs()).Parse(w.Body)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, map[string]interface{}{
+		"Report":  r,
+		"Diff":    d,
+		"Summary": summarize(r, d),
+	}); err != nil {
+		return nil, err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("the rendered webhook body is not valid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+func summarize(r *report.Report, d *report.DiffReport) *Summary {
+	s := &Summary{
+		Repository: r.Repository,
+		Ref:        r.Ref,
+		Commit:     r.Commit,
+		Timestamp:  r.Timestamp,
+	}
+	if r.IsMeasuredCoverage() {
+		v := r.CoveragePercent()
+		s.Coverage = &v
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		v := r.CodeToTestRatioRatio()
+		s.CodeToTestRatio = &v
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		v := r.TestExecutionTimeNano()
+		s.TestExecutionTime = &v
+	}
+	if d == nil || d.ReportA == nil || d.ReportB == nil {
+		return s
+	}
+	a, b := d.ReportA, d.ReportB
+	s.Diff = &SummaryDiff{}
+	if a.IsMeasuredCoverage() && b.IsMeasuredCoverage() {
+		v := b.CoveragePercent() - a.CoveragePercent()
+		s.Diff.Coverage = &v
+	}
+	if a.IsMeasuredCodeToTestRatio() && b.IsMeasuredCodeToTestRatio() {
+		v := b.CodeToTestRatioRatio() - a.CodeToTestRatioRatio()
+		s.Diff.CodeToTestRatio = &v
+	}
+	if a.IsMeasuredTestExecutionTime() && b.IsMeasuredTestExecutionTime() {
+		v := b.TestExecutionTimeNano() - a.TestExecutionTimeNano()
+		s.Diff.TestExecutionTime = &v
+	}
+	return s
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := w.client.Do(req)
+	if err != nil {
+		return !errors.Is(err, context.Canceled), err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook responded with status %d (%s)", res.StatusCode, w.URL)
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500, err
+}
+
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		},
+		"env": os.Getenv,
+	}
+}
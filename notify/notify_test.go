@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+	"github.com/lestrrat-go/backoff/v2"
+)
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		body      string
+		statuses  []int
+		wantErr   bool
+		wantCount int
+		wantBody  string
+	}{
+		{`{"text": "coverage {{ printf "%.1f" .Report.CoveragePercent }}%"}`, []int{200}, false, 1, `{"text": "coverage 50.0%"}`},
+		{`{"text": {{ json .Report.Repository }}}`, []int{500, 502, 204}, false, 3, `{"text": "owner/repo"}`},
+		{`{"diff": {{ if .Diff }}true{{ else }}false{{ end }}}`, []int{429, 200}, false, 2, `{"diff": false}`},
+		{`{"text": "ok"}`, []int{400}, true, 1, `{"text": "ok"}`},
+		{`{"text": "ok"}`, []int{500, 500, 500, 500}, true, 4, `{"text": "ok"}`},
+		{`{"text": {{ .Report.Repository }}}`, []int{200}, true, 0, ""},
+	}
+	for _, tt := range tests {
+		count := 0
+		var got string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = string(b)
+			if r.Header.Get("Authorization") != "Bearer xxx" {
+				t.Errorf("got %v\nwant %v", r.Header.Get("Authorization"), "Bearer xxx")
+			}
+			w.WriteHeader(tt.statuses[count])
+			count += 1
+		}))
+		r := &report.Report{
+			Repository: "owner/repo",
+			Coverage:   &coverage.Coverage{Total: 10, Covered: 5},
+		}
+		w := NewWebhook(ts.URL, map[string]string{"Authorization": "Bearer xxx"}, tt.body)
+		w.policy = backoff.Exponential(
+			backoff.WithMinInterval(time.Millisecond),
+			backoff.WithMaxInterval(10*time.Millisecond),
+			backoff.WithMaxRetries(3),
+		)
+		if err := w.Send(context.Background(), r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+		ts.Close()
+		if count != tt.wantCount {
+			t.Errorf("got %v\nwant %v", count, tt.wantCount)
+		}
+		if got != tt.wantBody {
+			t.Errorf("got %v\nwant %v", got, tt.wantBody)
+		}
+	}
+}
+
+func TestDefaultBody(t *testing.T) {
+	r := &report.Report{
+		Repository: "owner/repo",
+		Coverage: &coverage.Coverage{Total: 10, Covered: 5, Files: coverage.FileCoverages{
+			&coverage.FileCoverage{File: "path/to/secret.go", Total: 10, Covered: 5},
+		}},
+	}
+	rPrev := &report.Report{
+		Repository: "owner/repo",
+		Coverage:   &coverage.Coverage{Total: 10, Covered: 4},
+	}
+	w := NewWebhook("http://example.com", nil, "")
+	b, err := w.render(r, rPrev.Compare(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "path/to/secret.go") {
+		t.Errorf("got %s\nwant the summary metrics only", b)
+	}
+	got := &Summary{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Repository != "owner/repo" {
+		t.Errorf("got %v\nwant %v", got.Repository, "owner/repo")
+	}
+	if want := 50.0; got.Coverage == nil || *got.Coverage != want {
+		t.Errorf("got %v\nwant %v", got.Coverage, want)
+	}
+	if want := 10.0; got.Diff == nil || got.Diff.Coverage == nil || *got.Diff.Coverage != want {
+		t.Errorf("got %v\nwant %v", got.Diff, want)
+	}
+	if got.Diff.CodeToTestRatio != nil {
+		t.Errorf("got %v\nwant %v", got.Diff.CodeToTestRatio, nil)
+	}
+}
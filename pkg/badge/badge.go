@@ -13,7 +13,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"text/template"
+	"unicode/utf8"
 
 	"github.com/antchfx/xmlquery"
 	"github.com/golang/freetype/truetype"
@@ -27,24 +30,67 @@ const defaultMessageColor = "#007EC6"
 const fontSize = 11
 const dpi = 72
 
+// https://github.com/badges/shields/blob/master/spec/SPECIFICATION.md
+const (
+	StyleFlat        = "flat"
+	StyleFlatSquare  = "flat-square"
+	StyleForTheBadge = "for-the-badge"
+	StylePlastic     = "plastic"
+)
+
+var Styles = []string{StyleFlat, StyleFlatSquare, StyleForTheBadge, StylePlastic}
+
+const (
+	FormatSVG  = "svg"
+	FormatPNG  = "png"
+	FormatJSON = "json"
+)
+
+var Formats = []string{FormatSVG, FormatPNG, FormatJSON}
+
 type Badge struct {
 	Label        string
 	Message      string
 	LabelColor   string
 	MessageColor string
 	Icon         []byte
+	Style        string
+	Trend        []float64 // values of the metric ( oldest first ) drawn as a sparkline
 	drawer       *font.Drawer
 }
 
 //go:embed badge.svg.tmpl
 var badgeTmpl []byte
 
+//go:embed badge_flat_square.svg.tmpl
+var badgeFlatSquareTmpl []byte
+
+//go:embed badge_for_the_badge.svg.tmpl
+var badgeForTheBadgeTmpl []byte
+
+//go:embed badge_plastic.svg.tmpl
+var badgePlasticTmpl []byte
+
 // https://github.com/googlefonts/noto-fonts/blob/main/hinted/ttf/NotoSans/NotoSans-Medium.ttf
 //go:embed NotoSans-Medium.ttf
 var noto []byte
 
+var (
+	notoOnce sync.Once
+	notoTTF  *truetype.Font
+	notoErr  error
+)
+
+// notoFont returns the embedded font, which is parsed only once.
+func notoFont() (*truetype.Font, error) {
+	notoOnce.Do(func() {
+		notoTTF, notoErr = truetype.Parse(noto)
+	})
+	return notoTTF, notoErr
+}
+
 func New(l, m string) *Badge {
-	ttf, err := truetype.Parse(noto)
+	ttf, err := notoFont()
 	if err != nil {
 		panic(err)
 	}
@@ -54,6 +100,7 @@ func New(l, m string) *Badge {
 		Message:      m,
 		LabelColor:   defaultLabelColor,
 		MessageColor: defaultMessageColor,
+		Style:        StyleFlat,
 		drawer: &font.Drawer{
 			Face: truetype.NewFace(ttf, &truetype.Options{
 				Size:    fontSize,
@@ -78,54 +125,153 @@ func (b *Badge) AddIconFile(f string) error {
 	return nil
 }
 
+// layout is the geometry of the badge in pixels ( x positions and text lengths are scaled by 10 like SVG templates ).
+type layout struct {
+	Label             string
+	Message           string
+	LabelColor        string
+	MessageColor      string
+	Icon              string
+	Height            float64
+	Width             float64
+	LabelWidth        float64
+	MessageWidth      float64
+	LabelX            float64
+	MessageX          float64
+	LabelTextLength   float64
+	MessageTextLength float64
+	// Sparkline is the points of the polyline of the trend ( empty if no trend ).
+	Sparkline            string
+	SparklinePoints      []point
+	SparklineStrokeWidth float64
+}
+
 func (b *Badge) Render(wr io.Writer) error {
-	tmpl := template.Must(template.New("badge").Parse(string(badgeTmpl)))
+	var t []byte
+	switch b.Style {
+	case StyleFlat, "":
+		t = badgeTmpl
+	case StyleFlatSquare:
+		t = badgeFlatSquareTmpl
+	case StyleForTheBadge:
+		t = badgeForTheBadgeTmpl
+	case StylePlastic:
+		t = badgePlasticTmpl
+	default:
+		return fmt.Errorf("invalid badge style: %s", b.Style)
+	}
+	tmpl := template.Must(template.New("badge").Parse(string(t)))
+
+	icon, err := b.iconURI()
+	if err != nil {
+		return err
+	}
+
+	d := b.layout(icon != "")
+	d.Icon = icon
+	if err := tmpl.Execute(wr, d); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b *Badge) layout(hasIcon bool) *layout {
+	var l *layout
+	switch b.Style {
+	case StyleForTheBadge:
+		l = b.forTheBadgeLayout(hasIcon)
+	case StylePlastic:
+		l = b.flatLayout(hasIcon)
+		l.Height = 18
+	default:
+		l = b.flatLayout(hasIcon)
+	}
+	l.LabelColor = b.LabelColor
+	l.MessageColor = b.MessageColor
+	b.addSparkline(l)
+	return l
+}
 
+func (b *Badge) flatLayout(hasIcon bool) *layout {
 	// https://github.com/badges/shields/tree/master/spec
 	lw := 6 + b.stringWidth(b.Label) + 4
 	mw := 4 + b.stringWidth(b.Message) + 6
 	lx := lw * 10 / 2
 	mx := (lw * 10) + (mw * 10 / 2)
 	iw := 0.0
-	var icon string
-	if b.Icon != nil {
+	if hasIcon {
 		iw = 15.5
-		if issvg.Is(b.Icon) {
-			imgdoc, err := xmlquery.Parse(bytes.NewReader(b.Icon))
-			if err != nil {
-				return err
-			}
-			s := xmlquery.FindOne(imgdoc, "//svg")
-			icon = fmt.Sprintf("data:image/svg+xml;base64,%s", base64.StdEncoding.EncodeToString([]byte(s.OutputXML(true))))
-		} else {
-			_, format, err := image.DecodeConfig(bytes.NewReader(b.Icon))
-			if err != nil {
-				return err
-			}
-			icon = fmt.Sprintf("data:image/%s;base64,%s", format, base64.StdEncoding.EncodeToString(b.Icon))
-		}
 	}
+	return &layout{
+		Label:        b.Label,
+		Message:      b.Message,
+		Height:       20,
+		Width:        lw + mw + iw,
+		LabelWidth:   lw + iw,
+		MessageWidth: mw,
+		LabelX:       lx + (iw * 10),
+		MessageX:     mx + (iw * 10),
+	}
+}
 
-	d := map[string]interface{}{
-		"Label":        b.Label,
-		"Message":      b.Message,
-		"LabelColor":   b.LabelColor,
-		"MessageColor": b.MessageColor,
-		"Width":        lw + mw + iw,
-		"LabelWidth":   lw + iw,
-		"MessageWidth": mw,
-		"LabelX":       lx + (iw * 10),
-		"MessageX":     mx + (iw * 10),
-		"Icon":         icon,
+// forTheBadgeLayout measures the uppercased texts with the letter spacing of for-the-badge style.
+func (b *Badge) forTheBadgeLayout(hasIcon bool) *layout {
+	const (
+		padding       = 12
+		letterSpacing = 1.25
+		// for-the-badge style uses 10px font
+		scale = 10.0 / fontSize
+	)
+	label := strings.ToUpper(b.Label)
+	message := strings.ToUpper(b.Message)
+	ltw := b.textWidth(label)*scale + letterSpacing*float64(utf8.RuneCountInString(label))
+	mtw := b.textWidth(message)*scale + letterSpacing*float64(utf8.RuneCountInString(message))
+	lw := padding + ltw + padding
+	mw := padding + mtw + padding
+	iw := 0.0
+	if hasIcon {
+		iw = 18
 	}
-	if err := tmpl.Execute(wr, d); err != nil {
-		return err
+	return &layout{
+		Label:             label,
+		Message:           message,
+		Height:            28,
+		Width:             lw + mw + iw,
+		LabelWidth:        lw + iw,
+		MessageWidth:      mw,
+		LabelX:            (iw + lw/2) * 10,
+		MessageX:          (iw + lw + mw/2) * 10,
+		LabelTextLength:   ltw * 10,
+		MessageTextLength: mtw * 10,
 	}
+}
 
-	return nil
+func (b *Badge) iconURI() (string, error) {
+	if b.Icon == nil {
+		return "", nil
+	}
+	if issvg.Is(b.Icon) {
+		imgdoc, err := xmlquery.Parse(bytes.NewReader(b.Icon))
+		if err != nil {
+			return "", err
+		}
+		s := xmlquery.FindOne(imgdoc, "//svg")
+		return fmt.Sprintf("data:image/svg+xml;base64,%s", base64.StdEncoding.EncodeToString([]byte(s.OutputXML(true)))), nil
+	}
+	_, format, err := image.DecodeConfig(bytes.NewReader(b.Icon))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data:image/%s;base64,%s", format, base64.StdEncoding.EncodeToString(b.Icon)), nil
 }
 
 func (b *Badge) stringWidth(s string) float64 {
+	return b.textWidth(s) + 10 // 10 is heuristic
+}
+
+// textWidth returns the width of the text drawn by the font.
+func (b *Badge) textWidth(s string) float64 {
 	converted := []rune{}
 	for _, c := range s {
 		if utf8string.NewString(string([]rune{c})).IsASCII() {
@@ -135,7 +281,7 @@ func (b *Badge) stringWidth(s string) float64 {
 		}
 	}
 	w := b.drawer.MeasureString(string(converted))
-	return float64(w)/64 + 10 // 10 is heuristic
+	return float64(w) / 64
 }
 
 func ColorToHexRGB(c color.Color) string {
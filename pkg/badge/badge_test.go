@@ -0,0 +1,267 @@
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRenderStyle(t *testing.T) {
+	tests := []struct {
+		style   string
+		want    []string
+		wantErr bool
+	}{
+		{"", []string{`height="20"`, `rx="3"`, ">coverage</text>"}, false},
+		{StyleFlat, []string{`height="20"`, `rx="3"`, ">coverage</text>"}, false},
+		{StyleFlatSquare, []string{`height="20"`, `shape-rendering="crispEdges"`, ">coverage</text>"}, false},
+		{StylePlastic, []string{`height="18"`, `rx="4"`, ">coverage</text>"}, false},
+		{StyleForTheBadge, []string{`height="28"`, ">COVERAGE</text>", ">80.0%</text>", `textLength=`}, false},
+		{"popout", nil, true},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		b.Style = tt.style
+		buf := new(bytes.Buffer)
+		if err := b.Render(buf); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		got := buf.String()
+		for _, w := range tt.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("got %v\nwant %v", got, w)
+			}
+		}
+	}
+}
+
+func TestForTheBadgeTextWidth(t *testing.T) {
+	lower := New("coverage", "ok")
+	upper := New("COVERAGE", "OK")
+	got := lower.forTheBadgeLayout(false)
+	want := upper.forTheBadgeLayout(false)
+	if got.Width != want.Width {
+		t.Errorf("got %v\nwant %v", got.Width, want.Width)
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	icon := []byte(`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0,0L10,0L10,10Z" style="fill:rgb(149,157,165);"/></svg>`)
+	unsupported := []byte(`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><circle cx="5" cy="5" r="5" fill="currentColor"/></svg>`)
+	tests := []struct {
+		style    string
+		scale    int
+		icon     []byte
+		wantIcon bool
+		wantH    int
+		wantErr  bool
+	}{
+		{StyleFlat, 1, icon, true, 20, false},
+		{StyleFlat, 2, icon, true, 40, false},
+		{StyleFlatSquare, 1, nil, false, 20, false},
+		{StylePlastic, 2, icon, true, 36, false},
+		{StyleForTheBadge, 2, icon, true, 56, false},
+		// the badge without the icon is rendered
+		{StyleFlat, 1, unsupported, false, 20, false},
+		{StyleFlat, 0, nil, false, 0, true},
+		{StyleFlat, MaxPNGScale + 1, nil, false, 0, true},
+		{"popout", 1, nil, false, 0, true},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		b.Style = tt.style
+		if tt.icon != nil {
+			if err := b.AddIcon(tt.icon); err != nil {
+				t.Fatal(err)
+			}
+		}
+		buf := new(bytes.Buffer)
+		if err := b.RenderPNG(buf, tt.scale); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		img, err := png.Decode(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := img.Bounds().Dy(); got != tt.wantH {
+			t.Errorf("got %v\nwant %v", got, tt.wantH)
+		}
+		if want := int(math.Ceil(b.layout(tt.wantIcon).Width * float64(tt.scale))); img.Bounds().Dx() != want {
+			t.Errorf("got %v\nwant %v", img.Bounds().Dx(), want)
+		}
+	}
+}
+
+func TestRasterizeIcon(t *testing.T) {
+	icon := []byte(`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0,0 h5 v10 H0 z" fill="#f00"/></svg>`)
+	img, err := rasterizeIcon(icon, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := img.At(2, 5).RGBA(); a == 0 {
+		t.Errorf("got transparent pixel at (2, 5)")
+	}
+	if _, _, _, a := img.At(7, 5).RGBA(); a != 0 {
+		t.Errorf("got opaque pixel at (7, 5)")
+	}
+	if _, err := rasterizeIcon([]byte(`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="M0,0 X5,5"/></svg>`), 10); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestRasterizeIconWithFillProperties(t *testing.T) {
+	tests := []struct {
+		icon    string
+		inside  [][2]int
+		outside [][2]int
+		wantErr bool
+	}{
+		// fill inherited from <g>
+		{`<g fill="#f00"><path d="M0,0 h5 v10 H0 z"/></g>`, [][2]int{{2, 5}}, [][2]int{{7, 5}}, false},
+		{`<g style="fill:#f00"><path d="M0,0 h5 v10 H0 z" fill="none"/></g>`, nil, [][2]int{{2, 5}}, false},
+		// the hole of the subpaths in the same direction
+		{`<path d="M0,0 H10 V10 H0 Z M3,3 H7 V7 H3 Z" fill="#f00" fill-rule="evenodd"/>`, [][2]int{{1, 1}}, [][2]int{{5, 5}}, false},
+		{`<g style="fill-rule:evenodd"><path d="M0,0 H10 V10 H0 Z M3,3 H7 V7 H3 Z" fill="#f00"/></g>`, [][2]int{{1, 1}}, [][2]int{{5, 5}}, false},
+		{`<path d="M0,0 H10 V10 H0 Z M3,3 H7 V7 H3 Z" fill="#f00"/>`, [][2]int{{1, 1}, {5, 5}}, nil, false},
+		{`<g transform="scale(2)"><path d="M0,0 h5 v10 H0 z"/></g>`, nil, nil, true},
+		{`<path d="M0,0 h5 v10 H0 z" stroke="#000"/>`, nil, nil, true},
+		{`<path d="M0,0 h5 v10 H0 z" fill="currentColor"/>`, nil, nil, true},
+		{`<rect width="5" height="10"/>`, nil, nil, true},
+	}
+	for _, tt := range tests {
+		icon := []byte(fmt.Sprintf(`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg">%s</svg>`, tt.icon))
+		img, err := rasterizeIcon(icon, 10)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.icon, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want error", tt.icon)
+		}
+		for _, p := range tt.inside {
+			if _, _, _, a := img.At(p[0], p[1]).RGBA(); a == 0 {
+				t.Errorf("%s: got transparent pixel at %v", tt.icon, p)
+			}
+		}
+		for _, p := range tt.outside {
+			if _, _, _, a := img.At(p[0], p[1]).RGBA(); a != 0 {
+				t.Errorf("%s: got opaque pixel at %v", tt.icon, p)
+			}
+		}
+	}
+}
+
+func TestRasterizeIconWithArcAndSmoothCurves(t *testing.T) {
+	tests := []struct {
+		d       string
+		inside  [][2]int
+		outside [][2]int
+	}{
+		// circle of radius 4 at (5, 5)
+		{"M1,5 A4,4 0 1 1 9,5 A4,4 0 1 1 1,5 Z", [][2]int{{5, 5}, {2, 5}, {5, 8}}, [][2]int{{0, 0}, {9, 9}}},
+		// the same circle with the relative command and the compact flags
+		{"M1,5 a4 4 0 1 1 8 0 a4 4 0 1 1-8 0z", [][2]int{{5, 5}, {2, 5}, {5, 8}}, [][2]int{{0, 0}, {9, 9}}},
+		{"M1,5a4 4 0 118 0a4 4 0 11-8 0z", [][2]int{{5, 5}, {2, 5}, {5, 8}}, [][2]int{{0, 0}, {9, 9}}},
+		// smooth curves
+		{"M0,5 C0,0 5,0 5,5 S10,10 10,5 V10 H0 Z", [][2]int{{2, 6}, {8, 8}}, [][2]int{{8, 1}}},
+		{"M0,5 Q2.5,0 5,5 T10,5 V10 H0 Z", [][2]int{{2, 6}, {8, 8}}, [][2]int{{2, 1}}},
+	}
+	for _, tt := range tests {
+		icon := []byte(fmt.Sprintf(`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><path d="%s" fill="#f00"/></svg>`, tt.d))
+		img, err := rasterizeIcon(icon, 10)
+		if err != nil {
+			t.Errorf("%s: %v", tt.d, err)
+			continue
+		}
+		for _, p := range tt.inside {
+			if _, _, _, a := img.At(p[0], p[1]).RGBA(); a == 0 {
+				t.Errorf("%s: got transparent pixel at %v", tt.d, p)
+			}
+		}
+		for _, p := range tt.outside {
+			if _, _, _, a := img.At(p[0], p[1]).RGBA(); a != 0 {
+				t.Errorf("%s: got opaque pixel at %v", tt.d, p)
+			}
+		}
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	tests := []struct {
+		style string
+		icon  bool
+		want  string
+	}{
+		{StyleFlat, false, `{"schemaVersion":1,"label":"coverage","message":"80.0%","color":"4C1","labelColor":"24292E","style":"flat"}` + "\n"},
+		{"", true, `{"schemaVersion":1,"label":"coverage","message":"80.0%","color":"4C1","labelColor":"24292E","logoSvg":"<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 10 10\"></svg>"}` + "\n"},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		b.MessageColor = "#4C1"
+		b.Style = tt.style
+		if tt.icon {
+			if err := b.AddIcon([]byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"></svg>`)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		buf := new(bytes.Buffer)
+		if err := b.RenderJSON(buf); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+
+	b := New("coverage", "80.0%")
+	b.Trend = []float64{50, 80}
+	if err := b.RenderJSON(new(bytes.Buffer)); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		trend []float64
+		want  string
+	}{
+		{nil, ""},
+		{[]float64{50}, ""},
+		{[]float64{50, 50}, "X0,10.0 X40,10.0"},
+		{[]float64{0, 50, 100}, "X0,15.0 X20,10.0 X40,5.0"},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		l := b.layout(false)
+		b.Trend = tt.trend
+		got := b.layout(false)
+		want := tt.want
+		x := l.LabelWidth + l.MessageWidth
+		for _, o := range []float64{0, 20, 40} {
+			want = strings.Replace(want, fmt.Sprintf("X%.0f,", o), fmt.Sprintf("%.1f,", x+o), 1)
+		}
+		if got.Sparkline != want {
+			t.Errorf("got %v\nwant %v", got.Sparkline, want)
+		}
+		if want != "" && got.Width != l.Width+sparklineWidth+6 {
+			t.Errorf("got %v\nwant %v", got.Width, l.Width+sparklineWidth+6)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package badge
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	issvg "github.com/h2non/go-is-svg"
+)
+
+// endpoint is the JSON schema of the shields.io endpoint badge.
+// https://shields.io/badges/endpoint-badge
+type endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	LabelColor    string `json:"labelColor"`
+	Style         string `json:"style,omitempty"`
+	LogoSvg       string `json:"logoSvg,omitempty"`
+}
+
+// RenderJSON renders the badge as a shields.io endpoint JSON.
+// The endpoint JSON can not represent the sparkline, so the badge with Trend can not be rendered.
+func (b *Badge) RenderJSON(wr io.Writer) error {
+	if len(b.Trend) > 0 {
+		return errors.New("the sparkline of the trend badge can not be rendered as JSON")
+	}
+	e := &endpoint{
+		SchemaVersion: 1,
+		Label:         b.Label,
+		Message:       b.Message,
+		Color:         endpointColor(b.MessageColor),
+		LabelColor:    endpointColor(b.LabelColor),
+		Style:         b.Style,
+	}
+	if b.Icon != nil && issvg.Is(b.Icon) {
+		e.LogoSvg = string(b.Icon)
+	}
+	enc := json.NewEncoder(wr)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(e)
+}
+
+// endpointColor returns the color without the leading `#` because shields.io accepts hex colors without it.
+func endpointColor(c string) string {
+	return strings.TrimPrefix(c, "#")
+}
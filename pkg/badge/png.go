@@ -0,0 +1,709 @@
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/golang/freetype/truetype"
+	issvg "github.com/h2non/go-is-svg"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+const iconSize = 14
+
+type gradientStop struct {
+	offset  float64
+	color   color.NRGBA
+	opacity float64
+}
+
+type pngStyle struct {
+	radius        float64
+	fontSize      float64
+	letterSpacing float64
+	baseline      float64
+	// shadow is the offset of the text shadow ( 0 means no shadow )
+	shadow   float64
+	iconX    float64
+	iconY    float64
+	gradient []gradientStop
+}
+
+var pngStyles = map[string]pngStyle{
+	StyleFlat: {
+		radius:   3,
+		fontSize: fontSize,
+		baseline: 14,
+		shadow:   1,
+		iconX:    5,
+		iconY:    3,
+		gradient: []gradientStop{
+			{0, color.NRGBA{0xbb, 0xbb, 0xbb, 0xff}, .1},
+			{1, color.NRGBA{0x00, 0x00, 0x00, 0xff}, .1},
+		},
+	},
+	StyleFlatSquare: {
+		fontSize: fontSize,
+		baseline: 14,
+		iconX:    5,
+		iconY:    3,
+	},
+	StylePlastic: {
+		radius:   4,
+		fontSize: fontSize,
+		baseline: 13,
+		shadow:   1,
+		iconX:    5,
+		iconY:    2,
+		gradient: []gradientStop{
+			{0, color.NRGBA{0xff, 0xff, 0xff, 0xff}, .7},
+			{.1, color.NRGBA{0xaa, 0xaa, 0xaa, 0xff}, .1},
+			{.9, color.NRGBA{0x00, 0x00, 0x00, 0xff}, .3},
+			{1, color.NRGBA{0x00, 0x00, 0x00, 0xff}, .5},
+		},
+	},
+	StyleForTheBadge: {
+		fontSize:      10,
+		letterSpacing: 1.25,
+		baseline:      17.5,
+		iconX:         9,
+		iconY:         7,
+	},
+}
+
+// MaxPNGScale is the maximum scale of the PNG badge, to bound the size of the image.
+const MaxPNGScale = 8
+
+// RenderPNG renders the badge as PNG with the same layout as the SVG.
+// scale is the device pixel ratio of the image ( 1 for 1x, 2 for 2x ) up to MaxPNGScale.
+// The SVG icon is rasterized with a subset of SVG: <path> elements in <svg> and <g> filled with hex or rgb() colors, without transform, opacity and stroke.
+// If the icon can not be rasterized, the badge is rendered without the icon.
+func (b *Badge) RenderPNG(wr io.Writer, scale int) error {
+	if scale < 1 || scale > MaxPNGScale {
+		return fmt.Errorf("invalid scale: %d ( 1-%d )", scale, MaxPNGScale)
+	}
+	style := b.Style
+	if style == "" {
+		style = StyleFlat
+	}
+	ps, ok := pngStyles[style]
+	if !ok {
+		return fmt.Errorf("invalid badge style: %s", b.Style)
+	}
+	s := float64(scale)
+	var icon image.Image
+	if b.Icon != nil {
+		var err error
+		icon, err = rasterizeIcon(b.Icon, int(iconSize*s))
+		if err != nil {
+			icon = nil
+		}
+	}
+	l := b.layout(icon != nil)
+	lc, err := parseColor(l.LabelColor)
+	if err != nil {
+		return err
+	}
+	mc, err := parseColor(l.MessageColor)
+	if err != nil {
+		return err
+	}
+
+	w := int(math.Ceil(l.Width * s))
+	h := int(math.Round(l.Height * s))
+	lw := int(math.Round(l.LabelWidth * s))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, image.Rect(0, 0, lw, h), image.NewUniform(lc), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(lw, 0, w, h), image.NewUniform(mc), image.Point{}, draw.Src)
+	drawGradient(img, ps.gradient)
+
+	if icon != nil {
+		p := image.Pt(int(math.Round(ps.iconX*s)), int(math.Round(ps.iconY*s)))
+		draw.Draw(img, icon.Bounds().Add(p), icon, image.Point{}, draw.Over)
+	}
+
+	ttf, err := notoFont()
+	if err != nil {
+		return err
+	}
+	face := truetype.NewFace(ttf, &truetype.Options{
+		Size:    ps.fontSize * s,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	})
+	shadow := color.NRGBA{0x01, 0x01, 0x01, 0x4d}
+	for _, t := range []struct {
+		text string
+		x    float64
+	}{
+		{l.Label, l.LabelX / 10},
+		{l.Message, l.MessageX / 10},
+	} {
+		if ps.shadow > 0 {
+			drawText(img, face, t.text, t.x*s, (ps.baseline+ps.shadow)*s, ps.letterSpacing*s, shadow)
+		}
+		drawText(img, face, t.text, t.x*s, ps.baseline*s, ps.letterSpacing*s, color.White)
+	}
+
+	if len(l.SparklinePoints) > 0 {
+		z := strokePolyline(w, h, l.SparklinePoints, l.SparklineStrokeWidth, s)
+		z.DrawOp = draw.Over
+		z.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{})
+	}
+
+	out := img
+	if ps.radius > 0 {
+		out = image.NewRGBA(img.Bounds())
+		z := roundedRect(float32(w), float32(h), float32(ps.radius*s))
+		z.Draw(out, out.Bounds(), img, image.Point{})
+	}
+	return png.Encode(wr, out)
+}
+
+func drawGradient(img *image.RGBA, stops []gradientStop) {
+	if len(stops) == 0 {
+		return
+	}
+	b := img.Bounds()
+	h := float64(b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		t := (float64(y-b.Min.Y) + 0.5) / h
+		c, o := stops[0].color, stops[0].opacity
+		for i := 1; i < len(stops); i++ {
+			prev, next := stops[i-1], stops[i]
+			if t < prev.offset {
+				break
+			}
+			if t > next.offset {
+				c, o = next.color, next.opacity
+				continue
+			}
+			r := (t - prev.offset) / (next.offset - prev.offset)
+			c = color.NRGBA{
+				R: uint8(float64(prev.color.R) + (float64(next.color.R)-float64(prev.color.R))*r),
+				G: uint8(float64(prev.color.G) + (float64(next.color.G)-float64(prev.color.G))*r),
+				B: uint8(float64(prev.color.B) + (float64(next.color.B)-float64(prev.color.B))*r),
+			}
+			o = prev.opacity + (next.opacity-prev.opacity)*r
+			break
+		}
+		c.A = uint8(math.Round(o * 255))
+		draw.Draw(img, image.Rect(b.Min.X, y, b.Max.X, y+1), image.NewUniform(c), image.Point{}, draw.Over)
+	}
+}
+
+// drawText draws the text centered at cx with the letter spacing.
+func drawText(dst draw.Image, face font.Face, text string, cx, baseline, spacing float64, c color.Color) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+	}
+	n := utf8.RuneCountInString(text)
+	width := float64(d.MeasureString(text))/64 + spacing*float64(n-1)
+	d.Dot = fixed.Point26_6{
+		X: fixed.Int26_6((cx - width/2) * 64),
+		Y: fixed.Int26_6(baseline * 64),
+	}
+	if spacing == 0 {
+		d.DrawString(text)
+		return
+	}
+	for _, r := range text {
+		d.DrawString(string(r))
+		d.Dot.X += fixed.Int26_6(spacing * 64)
+	}
+}
+
+func roundedRect(w, h, r float32) *vector.Rasterizer {
+	z := vector.NewRasterizer(int(w), int(h))
+	z.MoveTo(r, 0)
+	z.LineTo(w-r, 0)
+	z.QuadTo(w, 0, w, r)
+	z.LineTo(w, h-r)
+	z.QuadTo(w, h, w-r, h)
+	z.LineTo(r, h)
+	z.QuadTo(0, h, 0, h-r)
+	z.LineTo(0, r)
+	z.QuadTo(0, 0, r, 0)
+	z.ClosePath()
+	return z
+}
+
+// strokePolyline traces the polyline stroked with round caps and joins.
+// All segments and joins are traced in the same winding direction so that the overlaps are not cancelled.
+func strokePolyline(w, h int, points []point, width, scale float64) *vector.Rasterizer {
+	z := vector.NewRasterizer(w, h)
+	r := width * scale / 2
+	for i, p := range points {
+		// round cap or join
+		const n = 16
+		z.MoveTo(float32(p.X*scale+r), float32(p.Y*scale))
+		for j := 1; j < n; j++ {
+			a := -2 * math.Pi * float64(j) / n
+			z.LineTo(float32(p.X*scale+r*math.Cos(a)), float32(p.Y*scale+r*math.Sin(a)))
+		}
+		z.ClosePath()
+		if i == 0 {
+			continue
+		}
+		q := points[i-1]
+		dx, dy := (p.X-q.X)*scale, (p.Y-q.Y)*scale
+		d := math.Hypot(dx, dy)
+		if d == 0 {
+			continue
+		}
+		nx, ny := -dy/d*r, dx/d*r
+		z.MoveTo(float32(q.X*scale+nx), float32(q.Y*scale+ny))
+		z.LineTo(float32(p.X*scale+nx), float32(p.Y*scale+ny))
+		z.LineTo(float32(p.X*scale-nx), float32(p.Y*scale-ny))
+		z.LineTo(float32(q.X*scale-nx), float32(q.Y*scale-ny))
+		z.ClosePath()
+	}
+	return z
+}
+
+// rasterizeIcon renders the icon image ( SVG paths or raster image ) in size x size pixels.
+func rasterizeIcon(icon []byte, size int) (image.Image, error) {
+	if !issvg.Is(icon) {
+		src, _, err := image.Decode(bytes.NewReader(icon))
+		if err != nil {
+			return nil, err
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+		return dst, nil
+	}
+	doc, err := xmlquery.Parse(bytes.NewReader(icon))
+	if err != nil {
+		return nil, err
+	}
+	root := xmlquery.FindOne(doc, "//svg")
+	if root == nil {
+		return nil, fmt.Errorf("invalid svg icon")
+	}
+	minX, minY, vw, vh := 0.0, 0.0, float64(size), float64(size)
+	if vb := strings.Fields(strings.ReplaceAll(root.SelectAttr("viewBox"), ",", " ")); len(vb) == 4 {
+		v := make([]float64, 4)
+		for i, f := range vb {
+			v[i], err = strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid viewBox of svg icon: %s", root.SelectAttr("viewBox"))
+			}
+		}
+		minX, minY, vw, vh = v[0], v[1], v[2], v[3]
+	}
+	paths := []*iconPath{}
+	if err := collectIconPaths(root, iconPath{fill: "black", fillRule: "nonzero"}, &paths); err != nil {
+		return nil, err
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	sx := float64(size) / vw
+	sy := float64(size) / vh
+	tr := func(x, y float64) (float32, float32) {
+		return float32((x - minX) * sx), float32((y - minY) * sy)
+	}
+	for _, p := range paths {
+		if p.fill == "none" {
+			continue
+		}
+		c, err := parseColor(p.fill)
+		if err != nil {
+			return nil, err
+		}
+		mask, err := fillPath(p.d, p.fillRule, size, tr)
+		if err != nil {
+			return nil, err
+		}
+		draw.DrawMask(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	return dst, nil
+}
+
+// iconPath is the <path> of the SVG icon with the inherited fill properties.
+type iconPath struct {
+	d        string
+	fill     string
+	fillRule string
+}
+
+// unsupportedIconProperties is the properties of the SVG icon that rasterizeIcon does not support.
+var unsupportedIconProperties = []string{"transform", "opacity", "fill-opacity", "stroke", "clip-path", "mask", "filter"}
+
+// collectIconPaths collects the <path> elements under n. Only <svg>, <g> and <path> elements are supported.
+func collectIconPaths(n *xmlquery.Node, inherited iconPath, paths *[]*iconPath) error {
+	switch n.Data {
+	case "title", "desc", "metadata":
+		return nil
+	case "svg", "g", "path":
+	default:
+		return fmt.Errorf("unsupported element of svg icon: <%s>", n.Data)
+	}
+	props := map[string]string{}
+	for _, a := range n.Attr {
+		props[a.Name.Local] = strings.TrimSpace(a.Value)
+	}
+	for _, decl := range strings.Split(n.SelectAttr("style"), ";") {
+		kv := strings.SplitN(decl, ":", 2)
+		if len(kv) == 2 {
+			props[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	for _, k := range unsupportedIconProperties {
+		if v, ok := props[k]; ok && !(k == "stroke" && v == "none") {
+			return fmt.Errorf("unsupported property of svg icon: %s", k)
+		}
+	}
+	cur := inherited
+	if v, ok := props["fill"]; ok && v != "inherit" {
+		cur.fill = v
+	}
+	if v, ok := props["fill-rule"]; ok && v != "inherit" {
+		if v != "nonzero" && v != "evenodd" {
+			return fmt.Errorf("invalid fill-rule of svg icon: %s", v)
+		}
+		cur.fillRule = v
+	}
+	if n.Data == "path" {
+		cur.d = props["d"]
+		*paths = append(*paths, &cur)
+		return nil
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != xmlquery.ElementNode {
+			continue
+		}
+		if err := collectIconPaths(c, cur, paths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillPath returns the alpha mask of the path filled with the fill rule.
+// The evenodd rule is approximated by combining the masks of the subpaths with XOR, which is exact for non-overlapping edges.
+func fillPath(d, fillRule string, size int, tr func(x, y float64) (float32, float32)) (*image.Alpha, error) {
+	subpaths := []string{d}
+	if fillRule == "evenodd" {
+		subpaths = splitSubpaths(d)
+	}
+	mask := image.NewAlpha(image.Rect(0, 0, size, size))
+	for i, sp := range subpaths {
+		z := vector.NewRasterizer(size, size)
+		if err := tracePath(z, sp, tr); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			z.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+			continue
+		}
+		m := image.NewAlpha(mask.Bounds())
+		z.Draw(m, m.Bounds(), image.Opaque, image.Point{})
+		for j := range mask.Pix {
+			a, b := float64(mask.Pix[j])/0xff, float64(m.Pix[j])/0xff
+			mask.Pix[j] = uint8(math.Round((a + b - 2*a*b) * 0xff))
+		}
+	}
+	return mask, nil
+}
+
+// splitSubpaths splits the path data into the subpaths starting with the absolute moveto.
+// A subpath starting with the relative moveto is kept with the previous one because its start depends on it.
+func splitSubpaths(d string) []string {
+	subpaths := []string{}
+	start := 0
+	for i := 1; i < len(d); i++ {
+		if d[i] == 'M' {
+			subpaths = append(subpaths, d[start:i])
+			start = i
+		}
+	}
+	return append(subpaths, d[start:])
+}
+
+var pathTokenRe = regexp.MustCompile(`[a-zA-Z]|[-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?`)
+
+// tracePath traces the SVG path data ( M, L, H, V, C, S, Q, T, A and Z commands ) on the rasterizer.
+func tracePath(z *vector.Rasterizer, d string, tr func(x, y float64) (float32, float32)) error {
+	tokens := pathTokenRe.FindAllString(d, -1)
+	var cmd, prev string
+	var x, y, sx, sy float64
+	// cx, cy is the last control point of the curve to reflect for S and T commands
+	var cx, cy float64
+	i := 0
+	isNum := func(i int) bool {
+		if i >= len(tokens) {
+			return false
+		}
+		_, err := strconv.ParseFloat(tokens[i], 64)
+		return err == nil
+	}
+	nums := func(n int) ([]float64, error) {
+		v := make([]float64, n)
+		for j := 0; j < n; j++ {
+			if !isNum(i) {
+				return nil, fmt.Errorf("invalid path data: %s", d)
+			}
+			v[j], _ = strconv.ParseFloat(tokens[i], 64)
+			i++
+		}
+		return v, nil
+	}
+	// flag reads the flag of the arc, which may be written without separators ( e.g. `a1 1 0 011 1` ).
+	flag := func() (bool, error) {
+		if i >= len(tokens) || (tokens[i][0] != '0' && tokens[i][0] != '1') {
+			return false, fmt.Errorf("invalid path data: %s", d)
+		}
+		f := tokens[i][0] == '1'
+		if len(tokens[i]) > 1 {
+			tokens[i] = tokens[i][1:]
+		} else {
+			i++
+		}
+		return f, nil
+	}
+	for i < len(tokens) {
+		if !isNum(i) {
+			cmd = tokens[i]
+			i++
+		} else if cmd == "" {
+			return fmt.Errorf("invalid path data: %s", d)
+		}
+		rel := strings.ToLower(cmd) == cmd
+		ox, oy := 0.0, 0.0
+		if rel {
+			ox, oy = x, y
+		}
+		c := strings.ToUpper(cmd)
+		switch c {
+		case "M":
+			v, err := nums(2)
+			if err != nil {
+				return err
+			}
+			x, y = ox+v[0], oy+v[1]
+			sx, sy = x, y
+			z.MoveTo(tr(x, y))
+			// subsequent pairs are lineto commands
+			if rel {
+				cmd = "l"
+			} else {
+				cmd = "L"
+			}
+		case "L":
+			v, err := nums(2)
+			if err != nil {
+				return err
+			}
+			x, y = ox+v[0], oy+v[1]
+			z.LineTo(tr(x, y))
+		case "H":
+			v, err := nums(1)
+			if err != nil {
+				return err
+			}
+			x = ox + v[0]
+			z.LineTo(tr(x, y))
+		case "V":
+			v, err := nums(1)
+			if err != nil {
+				return err
+			}
+			y = oy + v[0]
+			z.LineTo(tr(x, y))
+		case "C", "S":
+			var x1, y1 float64
+			var v []float64
+			var err error
+			if c == "C" {
+				v, err = nums(6)
+				if err != nil {
+					return err
+				}
+				x1, y1 = ox+v[0], oy+v[1]
+				v = v[2:]
+			} else {
+				v, err = nums(4)
+				if err != nil {
+					return err
+				}
+				x1, y1 = x, y
+				if prev == "C" || prev == "S" {
+					x1, y1 = 2*x-cx, 2*y-cy
+				}
+			}
+			cx, cy = ox+v[0], oy+v[1]
+			x, y = ox+v[2], oy+v[3]
+			bx, by := tr(x1, y1)
+			ccx, ccy := tr(cx, cy)
+			dx, dy := tr(x, y)
+			z.CubeTo(bx, by, ccx, ccy, dx, dy)
+		case "Q", "T":
+			if c == "Q" {
+				v, err := nums(4)
+				if err != nil {
+					return err
+				}
+				cx, cy = ox+v[0], oy+v[1]
+				x, y = ox+v[2], oy+v[3]
+			} else {
+				v, err := nums(2)
+				if err != nil {
+					return err
+				}
+				if prev == "Q" || prev == "T" {
+					cx, cy = 2*x-cx, 2*y-cy
+				} else {
+					cx, cy = x, y
+				}
+				x, y = ox+v[0], oy+v[1]
+			}
+			bx, by := tr(cx, cy)
+			ccx, ccy := tr(x, y)
+			z.QuadTo(bx, by, ccx, ccy)
+		case "A":
+			v, err := nums(3)
+			if err != nil {
+				return err
+			}
+			large, err := flag()
+			if err != nil {
+				return err
+			}
+			sweep, err := flag()
+			if err != nil {
+				return err
+			}
+			e, err := nums(2)
+			if err != nil {
+				return err
+			}
+			x0, y0 := x, y
+			x, y = ox+e[0], oy+e[1]
+			traceArc(z, tr, x0, y0, v[0], v[1], v[2], large, sweep, x, y)
+		case "Z":
+			z.ClosePath()
+			x, y = sx, sy
+		default:
+			return fmt.Errorf("unsupported path command of svg icon: %s", cmd)
+		}
+		prev = c
+	}
+	return nil
+}
+
+// traceArc traces the elliptical arc from (x0, y0) to (x, y) as cubic Bézier curves.
+// See https://www.w3.org/TR/SVG11/implnote.html#ArcImplementationNotes
+func traceArc(z *vector.Rasterizer, tr func(x, y float64) (float32, float32), x0, y0, rx, ry, rot float64, large, sweep bool, x, y float64) {
+	if x0 == x && y0 == y {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		z.LineTo(tr(x, y))
+		return
+	}
+	phi := rot * math.Pi / 180
+	sinPhi, cosPhi := math.Sin(phi), math.Cos(phi)
+	dx, dy := (x0-x)/2, (y0-y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+	// scale up the radii if they are too small to reach the end point
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		rx *= math.Sqrt(l)
+		ry *= math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	co := 0.0
+	if num > 0 && den > 0 {
+		co = math.Sqrt(num / den)
+	}
+	if large == sweep {
+		co = -co
+	}
+	cx1 := co * rx * y1 / ry
+	cy1 := -co * ry * x1 / rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (x0+x)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (y0+y)/2
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+	// split the arc into segments of 90 degrees at most
+	n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	d := delta / float64(n)
+	k := 4.0 / 3.0 * math.Tan(d/4)
+	point := func(t float64) (float64, float64) {
+		ex, ey := rx*math.Cos(t), ry*math.Sin(t)
+		return cosPhi*ex - sinPhi*ey + cx, sinPhi*ex + cosPhi*ey + cy
+	}
+	deriv := func(t float64) (float64, float64) {
+		ex, ey := -rx*math.Sin(t), ry*math.Cos(t)
+		return cosPhi*ex - sinPhi*ey, sinPhi*ex + cosPhi*ey
+	}
+	for j := 0; j < n; j++ {
+		t1 := theta + float64(j)*d
+		t2 := t1 + d
+		px1, py1 := point(t1)
+		px2, py2 := point(t2)
+		if j == n-1 {
+			px2, py2 = x, y
+		}
+		d1x, d1y := deriv(t1)
+		d2x, d2y := deriv(t2)
+		bx, by := tr(px1+k*d1x, py1+k*d1y)
+		ccx, ccy := tr(px2-k*d2x, py2-k*d2y)
+		ex, ey := tr(px2, py2)
+		z.CubeTo(bx, by, ccx, ccy, ex, ey)
+	}
+}
+
+var rgbColorRe = regexp.MustCompile(`^rgb\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*\)$`)
+
+// parseColor parses the hex color ( #RGB or #RRGGBB ) or rgb() notation.
+func parseColor(s string) (color.NRGBA, error) {
+	s = strings.TrimSpace(s)
+	if m := rgbColorRe.FindStringSubmatch(s); m != nil {
+		v := [3]uint8{}
+		for i := 0; i < 3; i++ {
+			n, err := strconv.ParseUint(m[i+1], 10, 8)
+			if err != nil {
+				return color.NRGBA{}, fmt.Errorf("invalid color: %s", s)
+			}
+			v[i] = uint8(n)
+		}
+		return color.NRGBA{v[0], v[1], v[2], 0xff}, nil
+	}
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color: %s", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color: %s", s)
+	}
+	return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+}
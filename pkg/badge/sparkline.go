@@ -0,0 +1,51 @@
+package badge
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const sparklineWidth = 40
+
+type point struct {
+	X float64
+	Y float64
+}
+
+// addSparkline widens the message part of the layout and plots the trend in it.
+func (b *Badge) addSparkline(l *layout) {
+	if len(b.Trend) < 2 {
+		return
+	}
+	padding := 6.0
+	strokeWidth := 1.2
+	if b.Style == StyleForTheBadge {
+		padding = 12
+		strokeWidth = 1.5
+	}
+	x := l.LabelWidth + l.MessageWidth
+	l.MessageWidth += sparklineWidth + padding
+	l.Width += sparklineWidth + padding
+
+	top := l.Height * 0.25
+	bottom := l.Height * 0.75
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range b.Trend {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	step := sparklineWidth / float64(len(b.Trend)-1)
+	points := []string{}
+	for i, v := range b.Trend {
+		y := (top + bottom) / 2
+		if max > min {
+			y = bottom - (v-min)/(max-min)*(bottom-top)
+		}
+		p := point{X: x + step*float64(i), Y: y}
+		l.SparklinePoints = append(l.SparklinePoints, p)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", p.X, p.Y))
+	}
+	l.Sparkline = strings.Join(points, " ")
+	l.SparklineStrokeWidth = strokeWidth
+}
@@ -0,0 +1,165 @@
+package chart
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultWidth  = 600
+	defaultHeight = 240
+	defaultColor  = "#0969DA"
+	yTicks        = 5
+)
+
+//go:embed chart.svg.tmpl
+var chartTmpl []byte
+
+type Point struct {
+	Time  time.Time
+	Value float64
+	// Label is the tooltip of the point
+	Label string
+}
+
+type LineChart struct {
+	Title  string
+	Points []Point
+	// YMin and YMax fix the range of the y axis ( auto if nil )
+	YMin   *float64
+	YMax   *float64
+	Format func(v float64) string
+	Color  string
+	Width  float64
+	Height float64
+}
+
+type tick struct {
+	Pos    float64
+	Label  string
+	Anchor string
+}
+
+type plot struct {
+	X     float64
+	Y     float64
+	Label string
+}
+
+func New(title string, points []Point) *LineChart {
+	return &LineChart{
+		Title:  title,
+		Points: points,
+		Format: func(v float64) string {
+			return fmt.Sprintf("%.1f", v)
+		},
+		Color:  defaultColor,
+		Width:  defaultWidth,
+		Height: defaultHeight,
+	}
+}
+
+// Render renders the line chart of the points ( sorted by time ) as SVG.
+func (c *LineChart) Render(wr io.Writer) error {
+	if len(c.Points) == 0 {
+		return errors.New("no points to render")
+	}
+	points := make([]Point, len(c.Points))
+	copy(points, c.Points)
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+
+	left, right := 64.0, c.Width-24
+	top, bottom := 40.0, c.Height-32
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		min = math.Min(min, p.Value)
+		max = math.Max(max, p.Value)
+	}
+	if c.YMin != nil {
+		min = *c.YMin
+	}
+	if c.YMax != nil {
+		max = *c.YMax
+	}
+	if max <= min {
+		// flat line in the middle
+		d := math.Max(math.Abs(min)*0.1, 1)
+		min, max = min-d, max+d
+	}
+	y := func(v float64) float64 {
+		return bottom - (v-min)/(max-min)*(bottom-top)
+	}
+
+	first, last := points[0].Time, points[len(points)-1].Time
+	span := last.Sub(first)
+	x := func(i int, t time.Time) float64 {
+		switch {
+		case len(points) == 1:
+			return (left + right) / 2
+		case span <= 0:
+			return left + (right-left)*float64(i)/float64(len(points)-1)
+		default:
+			return left + (right-left)*float64(t.Sub(first))/float64(span)
+		}
+	}
+
+	plots := []plot{}
+	line := []string{}
+	for i, p := range points {
+		pl := plot{
+			X:     round(x(i, p.Time)),
+			Y:     round(y(p.Value)),
+			Label: strings.TrimSpace(fmt.Sprintf("%s %s %s", p.Time.UTC().Format("2006-01-02"), c.Format(p.Value), p.Label)),
+		}
+		plots = append(plots, pl)
+		line = append(line, fmt.Sprintf("%v,%v", pl.X, pl.Y))
+	}
+	if len(plots) < 2 {
+		line = nil
+	}
+
+	yts := []tick{}
+	for i := 0; i < yTicks; i++ {
+		v := min + (max-min)*float64(i)/float64(yTicks-1)
+		yts = append(yts, tick{Pos: round(y(v)), Label: c.Format(v)})
+	}
+	xts := []tick{}
+	if len(plots) == 1 {
+		xts = append(xts, tick{Pos: plots[0].X, Label: first.UTC().Format("2006-01-02"), Anchor: "middle"})
+	} else {
+		xts = append(xts, tick{Pos: left, Label: first.UTC().Format("2006-01-02"), Anchor: "start"})
+		xts = append(xts, tick{Pos: right, Label: last.UTC().Format("2006-01-02"), Anchor: "end"})
+	}
+
+	tmpl := template.Must(template.New("chart").Funcs(template.FuncMap{
+		"add": func(a, b float64) float64 { return a + b },
+		"sub": func(a, b float64) float64 { return a - b },
+	}).Parse(string(chartTmpl)))
+	return tmpl.Execute(wr, map[string]interface{}{
+		"Title":  c.Title,
+		"Width":  c.Width,
+		"Height": c.Height,
+		"Left":   left,
+		"Right":  right,
+		"Bottom": bottom,
+		"Color":  c.Color,
+		"YTicks": yts,
+		"XTicks": xts,
+		"Points": plots,
+		"Line":   strings.Join(line, " "),
+	})
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
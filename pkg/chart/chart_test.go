@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		points      []Point
+		wantLine    bool
+		wantCircles int
+		wantErr     bool
+	}{
+		{nil, false, 0, true},
+		{[]Point{{Time: t0, Value: 50}}, false, 1, false},
+		{[]Point{{Time: t0.Add(48 * time.Hour), Value: 80}, {Time: t0, Value: 50}, {Time: t0.Add(24 * time.Hour), Value: 60}}, true, 3, false},
+		{[]Point{{Time: t0, Value: 50}, {Time: t0, Value: 50}}, true, 2, false},
+	}
+	for _, tt := range tests {
+		buf := new(bytes.Buffer)
+		err := New("Coverage", tt.points).Render(buf)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		got := buf.String()
+		if strings.Contains(got, "<polyline") != tt.wantLine {
+			t.Errorf("got %v\nwant %v", strings.Contains(got, "<polyline"), tt.wantLine)
+		}
+		if n := strings.Count(got, "<circle"); n != tt.wantCircles {
+			t.Errorf("got %v\nwant %v", n, tt.wantCircles)
+		}
+	}
+}
+
+func TestRenderSortsPointsByTime(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New("Coverage", []Point{
+		{Time: t0.Add(24 * time.Hour), Value: 100},
+		{Time: t0, Value: 0},
+	})
+	buf := new(bytes.Buffer)
+	if err := c.Render(buf); err != nil {
+		t.Fatal(err)
+	}
+	// the plot area is from ( 64, 40 ) to ( 600 - 24, 240 - 32 ) and the y axis is from 0 to 100
+	if want := `points="64,208 576,40"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("got %v\nwant %v", buf.String(), want)
+	}
+}
@@ -200,6 +200,83 @@ func (r *Report) FileCoveagesTable(files []*gh.PullRequestFile) string {
 	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
 }
 
+type LineRange struct {
+	File      string
+	StartLine int
+	EndLine   int
+}
+
+// UncoveredLineRanges returns the runs of uncovered lines added by the pull request.
+func (r *Report) UncoveredLineRanges(files []*gh.PullRequestFile) []*LineRange {
+	ranges := []*LineRange{}
+	if r.Coverage == nil {
+		return ranges
+	}
+	for _, f := range files {
+		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		var current *LineRange
+		prev := 0
+		for _, l := range f.AddedLines() {
+			if current != nil && l != prev+1 {
+				ranges = append(ranges, current)
+				current = nil
+			}
+			prev = l
+			blocks := fc.FindBlocksByLine(l)
+			if len(blocks) == 0 {
+				// not a coverable line
+				continue
+			}
+			if blocks.MaxCount() > 0 {
+				if current != nil {
+					ranges = append(ranges, current)
+					current = nil
+				}
+				continue
+			}
+			if current == nil {
+				current = &LineRange{File: f.Filename, StartLine: l, EndLine: l}
+				continue
+			}
+			current.EndLine = l
+		}
+		if current != nil {
+			ranges = append(ranges, current)
+		}
+	}
+	return ranges
+}
+
+// PatchCoverage returns the number of covered lines and coverable lines added by the pull request files.
+func (r *Report) PatchCoverage(files []*gh.PullRequestFile) (int, int) {
+	covered := 0
+	total := 0
+	if r.Coverage == nil {
+		return covered, total
+	}
+	for _, f := range files {
+		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		for _, l := range f.AddedLines() {
+			blocks := fc.FindBlocksByLine(l)
+			if len(blocks) == 0 {
+				// not a coverable line
+				continue
+			}
+			total += 1
+			if blocks.MaxCount() > 0 {
+				covered += 1
+			}
+		}
+	}
+	return covered, total
+}
+
 func (r *Report) CountMeasured() int {
 	c := 0
 	if r.IsMeasuredCoverage() {
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/pkg/coverage"
 	"github.com/k1LoW/octocov/pkg/ratio"
@@ -237,6 +238,75 @@ func TestFileCoveagesTable(t *testing.T) {
 	}
 }
 
+func TestUncoveredLineRanges(t *testing.T) {
+	block := func(start, end, count int) *coverage.BlockCoverage {
+		return &coverage.BlockCoverage{Type: coverage.TypeLOC, StartLine: &start, EndLine: &end, Count: &count}
+	}
+	fc := coverage.NewFileCoverage("github.com/owner/repo/path/to/main.go")
+	fc.Blocks = coverage.BlockCoverages{block(10, 12, 0), block(13, 13, 1), block(20, 21, 0), block(30, 31, 0)}
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{fc},
+		},
+	}
+	tests := []struct {
+		patch string
+		want  []*LineRange
+	}{
+		{"", []*LineRange{}},
+		{
+			"@@ -1,0 +10,12 @@\n+a\n+b\n+c\n+d\n+e\n+f\n+g\n+h\n+i\n+j\n+k\n+l",
+			[]*LineRange{{"path/to/main.go", 10, 12}, {"path/to/main.go", 20, 21}},
+		},
+		{
+			"@@ -28,4 +28,4 @@\n a\n b\n-c\n+c\n d",
+			[]*LineRange{{"path/to/main.go", 30, 30}},
+		},
+		{
+			"@@ -10,3 +10,3 @@\n+a\n b\n+c",
+			[]*LineRange{{"path/to/main.go", 10, 10}, {"path/to/main.go", 12, 12}},
+		},
+	}
+	for _, tt := range tests {
+		got := r.UncoveredLineRanges([]*gh.PullRequestFile{{Filename: "path/to/main.go", Patch: tt.patch}})
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestPatchCoverage(t *testing.T) {
+	block := func(start, end, count int) *coverage.BlockCoverage {
+		return &coverage.BlockCoverage{Type: coverage.TypeLOC, StartLine: &start, EndLine: &end, Count: &count}
+	}
+	fc := coverage.NewFileCoverage("github.com/owner/repo/path/to/main.go")
+	fc.Blocks = coverage.BlockCoverages{block(10, 12, 0), block(13, 13, 1), block(20, 21, 0), block(30, 31, 0)}
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{fc},
+		},
+	}
+	tests := []struct {
+		patch       string
+		wantCovered int
+		wantTotal   int
+	}{
+		{"", 0, 0},
+		{"@@ -1,0 +10,12 @@\n+a\n+b\n+c\n+d\n+e\n+f\n+g\n+h\n+i\n+j\n+k\n+l", 1, 6},
+		{"@@ -12,3 +12,3 @@\n a\n-b\n+b\n c", 1, 1},
+		{"@@ -40,1 +40,2 @@\n a\n+b", 0, 0},
+	}
+	for _, tt := range tests {
+		covered, total := r.PatchCoverage([]*gh.PullRequestFile{{Filename: "path/to/main.go", Patch: tt.patch}})
+		if covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", covered, tt.wantCovered)
+		}
+		if total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", total, tt.wantTotal)
+		}
+	}
+}
+
 func TestMergeExecutionTimes(t *testing.T) {
 	tests := []struct {
 		steps []gh.Step
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type trendPoint struct {
+	label string
+	value float64
+}
+
+// TrendChart returns Mermaid xychart-beta line charts of the metrics measured in r.
+// The points are the reports sorted by timestamp, followed by r.
+func TrendChart(reports []*Report, r *Report) string {
+	sorted := make([]*Report, len(reports))
+	copy(sorted, reports)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	sorted = append(sorted, r)
+
+	charts := []string{}
+	if r.IsMeasuredCoverage() {
+		points := []trendPoint{}
+		for _, rr := range sorted {
+			if rr.IsMeasuredCoverage() {
+				points = append(points, trendPoint{trendLabel(rr), rr.CoveragePercent()})
+			}
+		}
+		charts = append(charts, mermaidLineChart("Coverage", "Coverage (%)", "0 --> 100", "%.1f", points))
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		points := []trendPoint{}
+		for _, rr := range sorted {
+			if rr.IsMeasuredCodeToTestRatio() {
+				points = append(points, trendPoint{trendLabel(rr), rr.CodeToTestRatioRatio()})
+			}
+		}
+		charts = append(charts, mermaidLineChart("Code to Test Ratio", "Ratio (1:n)", "", "%.2f", points))
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		points := []trendPoint{}
+		max := 0.0
+		for _, rr := range sorted {
+			if rr.IsMeasuredTestExecutionTime() {
+				points = append(points, trendPoint{trendLabel(rr), rr.TestExecutionTimeNano()})
+				if rr.TestExecutionTimeNano() > max {
+					max = rr.TestExecutionTimeNano()
+				}
+			}
+		}
+		unit, axis := time.Second, "Time (s)"
+		if max >= float64(2*time.Minute) {
+			unit, axis = time.Minute, "Time (min)"
+		}
+		for i := range points {
+			points[i].value = points[i].value / float64(unit)
+		}
+		charts = append(charts, mermaidLineChart("Test Execution Time", axis, "", "%.1f", points))
+	}
+	return strings.Join(charts, "\n\n")
+}
+
+func mermaidLineChart(title, yLabel, yRange, format string, points []trendPoint) string {
+	labels := []string{}
+	values := []string{}
+	for _, p := range points {
+		labels = append(labels, fmt.Sprintf("%q", p.label))
+		values = append(values, fmt.Sprintf(format, p.value))
+	}
+	yAxis := fmt.Sprintf("%q", yLabel)
+	if yRange != "" {
+		yAxis = fmt.Sprintf("%s %s", yAxis, yRange)
+	}
+	return strings.Join([]string{
+		"```mermaid",
+		"xychart-beta",
+		fmt.Sprintf("  title %q", title),
+		fmt.Sprintf("  x-axis [%s]", strings.Join(labels, ", ")),
+		fmt.Sprintf("  y-axis %s", yAxis),
+		fmt.Sprintf("  line [%s]", strings.Join(values, ", ")),
+		"```",
+	}, "\n")
+}
+
+func trendLabel(r *Report) string {
+	if strings.HasPrefix(r.Ref, "refs/pull/") {
+		return strings.Replace(strings.TrimSuffix(strings.TrimSuffix(r.Ref, "/head"), "/merge"), "refs/pull/", "#", 1)
+	}
+	if len(r.Commit) > 7 {
+		return r.Commit[:7]
+	}
+	if r.Commit == "" {
+		return "-"
+	}
+	return r.Commit
+}
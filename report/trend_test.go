@@ -0,0 +1,61 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/ratio"
+)
+
+func TestTrendChart(t *testing.T) {
+	d := func(v time.Duration) *float64 {
+		f := float64(v)
+		return &f
+	}
+	reports := []*Report{
+		{
+			Commit:            "bbbbbbbbbb",
+			Timestamp:         time.Date(2006, 1, 3, 15, 4, 5, 0, time.UTC),
+			Coverage:          &coverage.Coverage{Total: 10, Covered: 6},
+			TestExecutionTime: d(90 * time.Second),
+		},
+		{
+			Commit:            "aaaaaaaaaa",
+			Timestamp:         time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+			Coverage:          &coverage.Coverage{Total: 10, Covered: 5},
+			TestExecutionTime: d(60 * time.Second),
+		},
+	}
+	r := &Report{
+		Ref:               "refs/pull/12/merge",
+		Commit:            "cccccccccc",
+		Coverage:          &coverage.Coverage{Total: 10, Covered: 7},
+		CodeToTestRatio:   &ratio.Ratio{Code: 100, Test: 120},
+		TestExecutionTime: d(150 * time.Second),
+	}
+	want := "```mermaid\n" +
+		"xychart-beta\n" +
+		"  title \"Coverage\"\n" +
+		"  x-axis [\"aaaaaaa\", \"bbbbbbb\", \"#12\"]\n" +
+		"  y-axis \"Coverage (%)\" 0 --> 100\n" +
+		"  line [50.0, 60.0, 70.0]\n" +
+		"```\n\n" +
+		"```mermaid\n" +
+		"xychart-beta\n" +
+		"  title \"Code to Test Ratio\"\n" +
+		"  x-axis [\"#12\"]\n" +
+		"  y-axis \"Ratio (1:n)\"\n" +
+		"  line [1.20]\n" +
+		"```\n\n" +
+		"```mermaid\n" +
+		"xychart-beta\n" +
+		"  title \"Test Execution Time\"\n" +
+		"  x-axis [\"aaaaaaa\", \"bbbbbbb\", \"#12\"]\n" +
+		"  y-axis \"Time (min)\"\n" +
+		"  line [1.0, 1.5, 2.5]\n" +
+		"```"
+	if got := TrendChart(reports, r); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}